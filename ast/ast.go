@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/codecrafters-io/interpreter-starter-go/token"
@@ -97,8 +98,10 @@ func (is *IfStatement) String() string {
 	out.WriteString(is.Condition.String())
 	out.WriteString(" ")
 	out.WriteString(is.Consequence.String())
-	out.WriteString(" else ")
-	out.WriteString(is.Alternative.String())
+	if is.Alternative != nil {
+		out.WriteString(" else ")
+		out.WriteString(is.Alternative.String())
+	}
 	return out.String()
 }
 
@@ -132,11 +135,14 @@ func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
 func (fs *ForStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString("for (")
-	out.WriteString(fs.Init.String())
-	out.WriteString("; ")
-	out.WriteString(fs.Condition.String())
-	out.WriteString("; ")
-	out.WriteString(fs.Increment.String())
+	for i, clause := range []Node{fs.Init, fs.Condition, fs.Increment} {
+		if i > 0 {
+			out.WriteString("; ")
+		}
+		if clause != nil {
+			out.WriteString(clause.String())
+		}
+	}
 	out.WriteString(") ")
 	out.WriteString(fs.Body.String())
 	return out.String()
@@ -151,7 +157,7 @@ func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
-	out.WriteString(rs.TokenLiteral() + " ")
+	out.WriteString("return ")
 	if rs.ReturnValue != nil {
 		out.WriteString(rs.ReturnValue.String())
 	}
@@ -193,6 +199,27 @@ func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
 func (sl *StringLiteral) String() string       { return sl.Token.Literal }
 
+// InterpolatedString is a string literal containing `${expr}` segments. Parts
+// holds the literal text as StringLiterals interleaved with the expressions.
+type InterpolatedString struct {
+	Token token.Token // the first INTERPOLATION token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode()      {}
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+func (is *InterpolatedString) String() string {
+	parts := []string{}
+	for _, part := range is.Parts {
+		if sl, ok := part.(*StringLiteral); ok {
+			parts = append(parts, strconv.Quote(sl.Value))
+		} else {
+			parts = append(parts, part.String())
+		}
+	}
+	return "(interpolate " + strings.Join(parts, " ") + ")"
+}
+
 type GroupExpression struct {
 	Token      token.Token // the LEFT_PAREN token
 	Expression Expression
@@ -271,6 +298,7 @@ type VarStatement struct {
 	Token token.Token // the token.VAR token
 	Name  *Identifier
 	Value Expression
+	Doc   string // attached `///` doc comment, if any
 }
 
 func (ls *VarStatement) statementNode()       {}
@@ -304,6 +332,64 @@ func (as *AssignExpression) String() string {
 	return out.String()
 }
 
+// IndexAssignExpression stores into a list element or map entry.
+type IndexAssignExpression struct {
+	Token  token.Token // the '=' token
+	Target *IndexExpression
+	Value  Expression
+}
+
+func (ia *IndexAssignExpression) expressionNode()      {}
+func (ia *IndexAssignExpression) TokenLiteral() string { return ia.Token.Literal }
+func (ia *IndexAssignExpression) String() string {
+	return ia.Target.String() + " = " + ia.Value.String() + ";"
+}
+
+// CompoundAssignExpression is `target op= value`. Target is an Identifier
+// or an IndexExpression and is evaluated once.
+type CompoundAssignExpression struct {
+	Token    token.Token // the operator token, e.g. +=
+	Target   Expression
+	Operator string // the operator without '=', e.g. +
+	Value    Expression
+}
+
+func (ca *CompoundAssignExpression) expressionNode()      {}
+func (ca *CompoundAssignExpression) TokenLiteral() string { return ca.Token.Literal }
+func (ca *CompoundAssignExpression) String() string {
+	return ca.Target.String() + " " + ca.Operator + "= " + ca.Value.String() + ";"
+}
+
+// UpdateExpression is a prefix or postfix `++` or `--`.
+type UpdateExpression struct {
+	Token    token.Token // the ++ or -- token
+	Operator string
+	Target   Expression
+	Prefix   bool
+}
+
+func (ue *UpdateExpression) expressionNode()      {}
+func (ue *UpdateExpression) TokenLiteral() string { return ue.Token.Literal }
+func (ue *UpdateExpression) String() string {
+	if ue.Prefix {
+		return ue.Operator + ue.Target.String()
+	}
+	return ue.Target.String() + ue.Operator
+}
+
+type ConditionalExpression struct {
+	Token       token.Token // the '?' token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (ce *ConditionalExpression) expressionNode()      {}
+func (ce *ConditionalExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *ConditionalExpression) String() string {
+	return fmt.Sprintf("(? %s %s %s)", ce.Condition.String(), ce.Consequence.String(), ce.Alternative.String())
+}
+
 type CallExpression struct {
 	Token     token.Token // The '(' token
 	Function  Expression  // Identifier or Function Literal
@@ -334,6 +420,7 @@ type FunctionLiteral struct {
 	Name       *Identifier
 	Parameters []*Identifier
 	Body       *BlockStatement
+	Doc        string // attached `///` doc comment, if any
 }
 
 func (fl *FunctionLiteral) expressionNode()      {}
@@ -355,3 +442,128 @@ func (fl *FunctionLiteral) String() string {
 
 	return out.String()
 }
+
+type ThrowStatement struct {
+	Token token.Token // the THROW token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("throw ")
+	out.WriteString(ts.Value.String())
+	out.WriteString(";")
+	return out.String()
+}
+
+// TestStatement is a `test "name" { ... }` block. It only runs under the
+// test command and is skipped otherwise.
+type TestStatement struct {
+	Token token.Token // the 'test' identifier
+	Name  string
+	Body  *BlockStatement
+}
+
+func (ts *TestStatement) statementNode()       {}
+func (ts *TestStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *TestStatement) String() string {
+	return "test " + strconv.Quote(ts.Name) + " " + ts.Body.String()
+}
+
+type TryStatement struct {
+	Token      token.Token // the TRY token
+	Block      *BlockStatement
+	CatchParam *Identifier // nil when the catch clause binds no name
+	Catch      *BlockStatement
+	Finally    *BlockStatement
+}
+
+func (ts *TryStatement) statementNode()       {}
+func (ts *TryStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *TryStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("try ")
+	out.WriteString(ts.Block.String())
+	if ts.Catch != nil {
+		out.WriteString(" catch ")
+		if ts.CatchParam != nil {
+			out.WriteString("(" + ts.CatchParam.String() + ") ")
+		}
+		out.WriteString(ts.Catch.String())
+	}
+	if ts.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(ts.Finally.String())
+	}
+	return out.String()
+}
+
+type GetExpression struct {
+	Token  token.Token // the '.' token
+	Object Expression
+	Name   *Identifier
+}
+
+func (ge *GetExpression) expressionNode()      {}
+func (ge *GetExpression) TokenLiteral() string { return ge.Token.Literal }
+func (ge *GetExpression) String() string {
+	return ge.Object.String() + "." + ge.Name.String()
+}
+
+// ImportStatement loads another source file as a module. It either binds the
+// whole module to Alias (`import "lib.lox" as lib;`) or copies the listed
+// Names into the current scope (`import { a, b } from "lib.lox";`).
+type ImportStatement struct {
+	Token token.Token // the IMPORT token
+	Path  string
+	Alias *Identifier
+	Names []*Identifier
+}
+
+func (is *ImportStatement) statementNode()       {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString("import ")
+	if is.Alias != nil {
+		out.WriteString(fmt.Sprintf("%q as %s", is.Path, is.Alias.String()))
+	} else {
+		names := []string{}
+		for _, n := range is.Names {
+			names = append(names, n.String())
+		}
+		out.WriteString("{ " + strings.Join(names, ", ") + " } from ")
+		out.WriteString(fmt.Sprintf("%q", is.Path))
+	}
+	out.WriteString(";")
+	return out.String()
+}
+
+type ListLiteral struct {
+	Token    token.Token // the '[' token
+	Elements []Expression
+}
+
+func (ll *ListLiteral) expressionNode()      {}
+func (ll *ListLiteral) TokenLiteral() string { return ll.Token.Literal }
+func (ll *ListLiteral) String() string {
+	elements := []string{}
+	for _, el := range ll.Elements {
+		elements = append(elements, el.String())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+type IndexExpression struct {
+	Token token.Token // the '[' token
+	Left  Expression
+	Index Expression
+}
+
+func (ie *IndexExpression) expressionNode()      {}
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IndexExpression) String() string {
+	return ie.Left.String() + "[" + ie.Index.String() + "]"
+}
@@ -0,0 +1,141 @@
+package ast
+
+import "reflect"
+
+// Inspect traverses the tree rooted at node in depth-first order, calling f
+// for each node. If f returns false, the children of that node are skipped.
+// Nil children, including typed nils left behind by parse errors, are skipped.
+func Inspect(node Node, f func(Node) bool) {
+	if isNil(node) || !f(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		for _, s := range n.Statements {
+			Inspect(s, f)
+		}
+	case *BlockStatement:
+		for _, s := range n.Statements {
+			Inspect(s, f)
+		}
+	case *ExpressionStatement:
+		Inspect(n.Expression, f)
+	case *IfStatement:
+		Inspect(n.Condition, f)
+		Inspect(n.Consequence, f)
+		Inspect(n.Alternative, f)
+	case *WhileStatement:
+		Inspect(n.Condition, f)
+		Inspect(n.Consequence, f)
+	case *ForStatement:
+		Inspect(n.Init, f)
+		Inspect(n.Condition, f)
+		Inspect(n.Increment, f)
+		Inspect(n.Body, f)
+	case *ReturnStatement:
+		Inspect(n.ReturnValue, f)
+	case *InterpolatedString:
+		for _, part := range n.Parts {
+			Inspect(part, f)
+		}
+	case *GroupExpression:
+		Inspect(n.Expression, f)
+	case *PrefixExpression:
+		Inspect(n.Right, f)
+	case *InfixExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Right, f)
+	case *PrintExpression:
+		Inspect(n.Expression, f)
+	case *VarStatement:
+		Inspect(n.Name, f)
+		Inspect(n.Value, f)
+	case *AssignExpression:
+		Inspect(n.Name, f)
+		Inspect(n.Value, f)
+	case *IndexAssignExpression:
+		Inspect(n.Target, f)
+		Inspect(n.Value, f)
+	case *CompoundAssignExpression:
+		Inspect(n.Target, f)
+		Inspect(n.Value, f)
+	case *UpdateExpression:
+		Inspect(n.Target, f)
+	case *ConditionalExpression:
+		Inspect(n.Condition, f)
+		Inspect(n.Consequence, f)
+		Inspect(n.Alternative, f)
+	case *CallExpression:
+		Inspect(n.Function, f)
+		for _, arg := range n.Arguments {
+			Inspect(arg, f)
+		}
+	case *FunctionLiteral:
+		Inspect(n.Name, f)
+		for _, param := range n.Parameters {
+			Inspect(param, f)
+		}
+		Inspect(n.Body, f)
+	case *ThrowStatement:
+		Inspect(n.Value, f)
+	case *TestStatement:
+		Inspect(n.Body, f)
+	case *TryStatement:
+		Inspect(n.Block, f)
+		Inspect(n.CatchParam, f)
+		Inspect(n.Catch, f)
+		Inspect(n.Finally, f)
+	case *GetExpression:
+		Inspect(n.Object, f)
+		Inspect(n.Name, f)
+	case *ImportStatement:
+		Inspect(n.Alias, f)
+		for _, name := range n.Names {
+			Inspect(name, f)
+		}
+	case *ListLiteral:
+		for _, el := range n.Elements {
+			Inspect(el, f)
+		}
+	case *IndexExpression:
+		Inspect(n.Left, f)
+		Inspect(n.Index, f)
+	}
+}
+
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// StatementLine returns the source line of a statement node, or 0 for
+// expressions, which are attributed to their enclosing statement.
+func StatementLine(node Node) int {
+	switch node := node.(type) {
+	case *ExpressionStatement:
+		return node.Token.Line
+	case *VarStatement:
+		return node.Token.Line
+	case *ReturnStatement:
+		return node.Token.Line
+	case *IfStatement:
+		return node.Token.Line
+	case *WhileStatement:
+		return node.Token.Line
+	case *ForStatement:
+		return node.Token.Line
+	case *ThrowStatement:
+		return node.Token.Line
+	case *TryStatement:
+		return node.Token.Line
+	case *ImportStatement:
+		return node.Token.Line
+	case *TestStatement:
+		return node.Token.Line
+	}
+	return 0
+}
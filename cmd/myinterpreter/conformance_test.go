@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// The conformance suite uses the annotation format of the reference Lox test
+// suite, so its files can be dropped in unchanged. Set LOX_CONFORMANCE_DIR to
+// run a different directory, such as a checkout of the official tests.
+var (
+	expectOutput       = regexp.MustCompile(`// expect: ?(.*)`)
+	expectRuntimeError = regexp.MustCompile(`// expect runtime error: (.+)`)
+	expectSyntaxError  = regexp.MustCompile(`// (?:\[(?:java )?line (\d+)\] )?(Error.*)`)
+)
+
+// expectation is what a conformance file says running it should produce.
+// A runtime error is expected to be the whole of stderr, so any extra
+// diagnostic or trace output counts as a mismatch.
+type expectation struct {
+	stdout []string
+	stderr []string
+	status int
+}
+
+func parseExpectation(source string) expectation {
+	var want expectation
+	for i, line := range strings.Split(source, "\n") {
+		if m := expectOutput.FindStringSubmatch(line); m != nil {
+			want.stdout = append(want.stdout, m[1])
+		} else if m := expectRuntimeError.FindStringSubmatch(line); m != nil {
+			want.stderr = append(want.stderr, m[1])
+			want.status = 70
+		} else if m := expectSyntaxError.FindStringSubmatch(line); m != nil {
+			lineNo := strconv.Itoa(i + 1)
+			if m[1] != "" {
+				lineNo = m[1]
+			}
+			want.stderr = append(want.stderr, "[line "+lineNo+"] "+m[2])
+			want.status = 65
+		}
+	}
+	return want
+}
+
+// outputLines splits captured output into lines, ignoring a trailing newline.
+func outputLines(out string) []string {
+	out = strings.TrimSuffix(out, "\n")
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+func TestConformance(t *testing.T) {
+	root := os.Getenv("LOX_CONFORMANCE_DIR")
+	if root == "" {
+		root = filepath.Join("testdata", "conformance")
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".lox" {
+			return err
+		}
+		name, _ := filepath.Rel(root, path)
+		t.Run(filepath.ToSlash(name), func(t *testing.T) {
+			source, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+			want := parseExpectation(string(source))
+
+			var stdout, stderr bytes.Buffer
+			status := execute("run", path, runOptions{}, &stdout, &stderr)
+
+			if status != want.status {
+				t.Errorf("expected exit status %d, got %d", want.status, status)
+			}
+			if got := outputLines(stdout.String()); strings.Join(got, "\n") != strings.Join(want.stdout, "\n") {
+				t.Errorf("stdout mismatch\nexpected:\n%s\ngot:\n%s", strings.Join(want.stdout, "\n"), strings.Join(got, "\n"))
+			}
+
+			if got := outputLines(stderr.String()); strings.Join(got, "\n") != strings.Join(want.stderr, "\n") {
+				t.Errorf("stderr mismatch\nexpected:\n%s\ngot:\n%s", strings.Join(want.stderr, "\n"), strings.Join(got, "\n"))
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk %s: %v", root, err)
+	}
+}
+
+func TestParseExpectation(t *testing.T) {
+	source := `print 1; // expect: 1
+print "a"; // expect: a
+var = 1; // Error at '=': Expect expression.
+// [line 9] Error at end: Expect '}' after block.
+// [c line 9] Error at end: ignored.
+-nil; // expect runtime error: Operand must be a number.`
+
+	want := parseExpectation(source)
+	if strings.Join(want.stdout, "|") != "1|a" {
+		t.Errorf("unexpected stdout expectations: %q", want.stdout)
+	}
+	expectedStderr := "[line 3] Error at '=': Expect expression.|[line 9] Error at end: Expect '}' after block.|Operand must be a number."
+	if strings.Join(want.stderr, "|") != expectedStderr {
+		t.Errorf("unexpected stderr expectations: %q", want.stderr)
+	}
+	if want.status != 70 {
+		t.Errorf("unexpected exit status expectation: %d", want.status)
+	}
+}
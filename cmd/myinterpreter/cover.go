@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/codecrafters-io/interpreter-starter-go/coverage"
+)
+
+// renderCoverage implements the cover command: it reads a profile written by
+// `run --coverage` and prints an annotated text or HTML report. Files whose
+// source can no longer be read are summarized without a listing.
+func renderCoverage(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cover", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "text", "output format: text or html")
+	out := fs.String("out", "", "write the report to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "Usage: ./your_program.sh cover [--format=text|html] [--out=file] <profile.json>")
+		return 1
+	}
+
+	render := coverage.Text
+	switch *format {
+	case "text":
+	case "html":
+		render = coverage.HTML
+	default:
+		fmt.Fprintf(stderr, "unknown cover format: %s\n", *format)
+		return 1
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "error reading file: %v\n", err)
+		return 1
+	}
+	profile, err := coverage.Read(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(stderr, "error reading coverage profile: %v\n", err)
+		return 1
+	}
+
+	sources := map[string]string{}
+	for _, file := range profile.Files {
+		if source, err := os.ReadFile(file.Path); err == nil {
+			sources[file.Path] = string(source)
+		}
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "error writing file: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := render(w, profile, sources); err != nil {
+		fmt.Fprintf(stderr, "error writing coverage report: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/codecrafters-io/interpreter-starter-go/doc"
+	"github.com/codecrafters-io/interpreter-starter-go/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/parser"
+)
+
+// generateDocs implements the doc command: it parses each file and writes
+// the combined API documentation as Markdown or HTML. It returns the exit
+// status, 65 if any file fails to parse.
+func generateDocs(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("doc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "markdown", "output format: markdown or html")
+	out := fs.String("out", "", "write the documentation to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "Usage: ./your_program.sh doc [--format=markdown|html] [--out=file] <filename>...")
+		return 1
+	}
+
+	render := doc.Markdown
+	switch *format {
+	case "markdown", "md":
+	case "html":
+		render = doc.HTML
+	default:
+		fmt.Fprintf(stderr, "unknown doc format: %s\n", *format)
+		return 1
+	}
+
+	var files []doc.File
+	for _, filename := range fs.Args() {
+		fileContents, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintf(stderr, "error reading file: %v\n", err)
+			return 1
+		}
+
+		p := parser.New(lexer.New(string(fileContents)))
+		program := p.ParseProgram()
+		if !p.CheckErrors(stderr) {
+			return 65
+		}
+		files = append(files, doc.Collect(filename, program))
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "error writing file: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := render(w, files); err != nil {
+		fmt.Fprintf(stderr, "error writing documentation: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/lint"
+	"github.com/codecrafters-io/interpreter-starter-go/parser"
+)
+
+// runLint implements the lint command. It checks every .lox file in the
+// given files and directories (default ".") and prints one line per finding.
+// It returns 0 when nothing worse than info was found, 1 for warnings,
+// errors or usage mistakes, and 65 when a file does not parse.
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	disable := flags.String("disable", "", "comma-separated rule IDs to skip")
+	severity := flags.String("severity", "", "comma-separated rule=severity overrides, e.g. shadowed-variable=info")
+	list := flags.Bool("list", false, "list the rules and exit")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if *list {
+		for _, rule := range lint.Rules {
+			fmt.Fprintf(stdout, "%-22s %-8s %s\n", rule.ID, rule.Severity, rule.Summary)
+		}
+		return 0
+	}
+
+	cfg, err := lintConfig(*disable, *severity)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := discoverFiles(paths, ".lox")
+	if err != nil {
+		fmt.Fprintf(stderr, "error finding files: %v\n", err)
+		return 1
+	}
+
+	status := 0
+	for _, filename := range files {
+		fileContents, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintf(stderr, "error reading file: %v\n", err)
+			return 1
+		}
+
+		p := parser.New(lexer.New(string(fileContents)))
+		program := p.ParseProgram()
+		if !p.CheckErrors(stderr) {
+			return 65
+		}
+
+		for _, d := range lint.Check(program, string(fileContents), cfg) {
+			fmt.Fprintf(stdout, "%s:%s\n", filename, d)
+			if d.Severity != lint.Info {
+				status = 1
+			}
+		}
+	}
+	return status
+}
+
+// lintConfig builds a lint.Config from the --disable and --severity flags.
+func lintConfig(disable, severity string) (lint.Config, error) {
+	cfg := lint.Config{Disabled: map[string]bool{}, Severity: map[string]lint.Severity{}}
+
+	for _, id := range splitList(disable) {
+		if _, ok := lint.LookupRule(id); !ok {
+			return cfg, fmt.Errorf("unknown lint rule: %s", id)
+		}
+		cfg.Disabled[id] = true
+	}
+
+	for _, override := range splitList(severity) {
+		id, level, _ := strings.Cut(override, "=")
+		if _, ok := lint.LookupRule(id); !ok {
+			return cfg, fmt.Errorf("unknown lint rule: %s", id)
+		}
+		s, ok := lint.ParseSeverity(level)
+		if !ok {
+			return cfg, fmt.Errorf("unknown severity for %s: %q (want error, warning or info)", id, level)
+		}
+		cfg.Severity[id] = s
+	}
+	return cfg, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,14 +1,22 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
+	"github.com/codecrafters-io/interpreter-starter-go/coverage"
 	"github.com/codecrafters-io/interpreter-starter-go/evaluator"
 	"github.com/codecrafters-io/interpreter-starter-go/lexer"
 	"github.com/codecrafters-io/interpreter-starter-go/object"
 	"github.com/codecrafters-io/interpreter-starter-go/parser"
+	"github.com/codecrafters-io/interpreter-starter-go/profile"
 	"github.com/codecrafters-io/interpreter-starter-go/token"
 )
 
@@ -27,11 +35,17 @@ func tokenize(filename string, stdout, stderr io.Writer) bool {
 		if tok.Type == token.UNTERMINATED_STRING {
 			fmt.Fprintf(stderr, "[line %d] Error: Unterminated string.\n", tok.Line)
 			ok = false
+		} else if tok.Type == token.UNTERMINATED_COMMENT {
+			fmt.Fprintf(stderr, "[line %d] Error: Unterminated comment.\n", tok.Line)
+			ok = false
+		} else if tok.Type == token.INVALID_ESCAPE {
+			fmt.Fprintf(stderr, "[line %d] Error: Invalid escape sequence: %s\n", tok.Line, tok.Lexeme)
+			ok = false
 		} else if tok.Type == token.ILLEGAL {
 			fmt.Fprintf(stderr, "[line %d] Error: Unexpected character: %s\n", tok.Line, string(tok.Lexeme))
 			ok = false
 		} else {
-			fmt.Fprintf(stdout, "%s %s %s\n", tok.Type, tok.Lexeme, tok.Literal)
+			fmt.Fprintf(stdout, "%s %s %s\n", tok.Type, singleLine(tok.Lexeme), singleLine(tok.Literal))
 		}
 	}
 
@@ -39,6 +53,22 @@ func tokenize(filename string, stdout, stderr io.Writer) bool {
 	return ok
 }
 
+// singleLine keeps a lexeme or literal on one output line, such as a
+// multi-line string or a decoded escape, by escaping backslashes and control
+// characters the way Go string literals do.
+func singleLine(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r == '\\' || unicode.IsControl(r) {
+			quoted := strconv.QuoteRune(r)
+			b.WriteString(quoted[1 : len(quoted)-1])
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func parse(filename string, stdout, stderr io.Writer) bool {
 	fileContents, err := os.ReadFile(filename)
 	if err != nil {
@@ -57,56 +87,224 @@ func parse(filename string, stdout, stderr io.Writer) bool {
 	return true
 }
 
-func evaluate(filename string, stdout, stderr io.Writer) bool {
+// runOptions holds the flags accepted by the evaluate and run commands.
+type runOptions struct {
+	seed          int64
+	seeded        bool
+	deterministic bool
+	coverage      string   // file to write the coverage profile to, if any
+	profile       string   // path prefix of the profiler output files, if any
+	profileTop    int      // number of functions in the profile table
+	pprof         bool     // also write the profile in pprof format
+	trace         bool     // log execution to stderr or traceOut
+	traceOut      string   // file to write the trace to instead of stderr
+	traceFuncs    []string // only trace calls to these functions
+	scriptArgs    []string // arguments after the filename, exposed as `args`
+}
+
+// parseRunOptions parses the flags that precede the filename of the evaluate
+// and run commands. Other commands take no flags and get args back as-is.
+func parseRunOptions(command string, args []string, stderr io.Writer) (runOptions, []string, bool) {
+	var opts runOptions
+	if command != "evaluate" && command != "run" {
+		return opts, args, true
+	}
+
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	seed := fs.Int64("seed", 0, "seed for the random number generator")
+	fs.BoolVar(&opts.deterministic, "deterministic", false, "freeze the clock and seed randomness for reproducible output")
+	fs.StringVar(&opts.coverage, "coverage", "", "write statement and branch coverage to this JSON file")
+	fs.StringVar(&opts.profile, "profile", "", "write a function profile to `prefix`.txt and prefix.folded")
+	fs.IntVar(&opts.profileTop, "profile-top", 20, "number of functions listed in the profile table")
+	fs.BoolVar(&opts.pprof, "pprof", false, "with --profile, also write prefix.pb.gz in pprof format")
+	fs.BoolVar(&opts.trace, "trace", false, "log each statement, call and assignment to stderr")
+	fs.StringVar(&opts.traceOut, "trace-out", "", "write the trace to this file instead of stderr (implies --trace)")
+	traceFuncs := fs.String("trace-func", "", "comma-separated `names` of the only functions to trace (implies --trace)")
+	if err := fs.Parse(args); err != nil {
+		return opts, nil, false
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts.seed, opts.seeded = *seed, true
+		}
+	})
+	if *traceFuncs != "" {
+		opts.traceFuncs = strings.Split(*traceFuncs, ",")
+	}
+	opts.trace = opts.trace || opts.traceOut != "" || len(opts.traceFuncs) > 0
+	return opts, fs.Args(), true
+}
+
+// evaluate runs a script and returns the process exit status: 65 for syntax
+// errors, 70 for runtime errors, or the code the script passed to exit().
+func evaluate(filename string, opts runOptions, stdout, stderr io.Writer) int {
 	fileContents, err := os.ReadFile(filename)
 	if err != nil {
 		fmt.Fprintf(stderr, "error reading file: %v\n", err)
-		return false
+		return 70
 	}
 
 	p := parser.New(lexer.New(string(fileContents)))
 
 	program := p.ParseProgram()
 	if !p.CheckErrors(stderr) {
-		os.Exit(65)
-		return false
+		return 65
 	}
 
 	env := object.NewEnvironment()
 	e := evaluator.NewEvaluator(&stdout, &stderr)
+	e.ScriptPath = filename
+	e.ModulePaths = filepath.SplitList(os.Getenv("LOX_PATH"))
+	if opts.deterministic {
+		e.Clock = evaluator.FrozenClock(time.Unix(0, 0))
+		e.Seed(0)
+	}
+	if opts.seeded {
+		e.Seed(opts.seed)
+	}
+	e.SetArgs(opts.scriptArgs)
+	if opts.coverage != "" {
+		e.Coverage = coverage.New()
+		e.Coverage.Register(filename, program)
+	}
 
+	if opts.trace {
+		w := stderr
+		if opts.traceOut != "" {
+			f, err := os.Create(opts.traceOut)
+			if err != nil {
+				fmt.Fprintf(stderr, "error writing trace: %v\n", err)
+				return 1
+			}
+			defer f.Close()
+			w = f
+		}
+		e.Tracer = evaluator.NewTracer(w, opts.traceFuncs)
+	}
+	if opts.profile != "" {
+		e.Profiler = profile.New()
+		e.Profiler.Start()
+	}
+
+	status := 0
 	evaluated := e.Eval(program, env)
-	return evaluated == nil || evaluated.Type() != object.ERROR_OBJ
+	if exit, ok := evaluated.(*object.Exit); ok {
+		status = exit.Code
+	} else if evaluated != nil && evaluated.Type() == object.ERROR_OBJ {
+		status = 70
+	}
+
+	if opts.coverage != "" {
+		if err := writeCoverage(opts.coverage, e.Coverage); err != nil {
+			fmt.Fprintf(stderr, "error writing coverage: %v\n", err)
+			if status == 0 {
+				status = 1
+			}
+		}
+	}
+	if opts.profile != "" {
+		e.Profiler.Stop()
+		if err := writeProfile(opts, e.Profiler); err != nil {
+			fmt.Fprintf(stderr, "error writing profile: %v\n", err)
+			if status == 0 {
+				status = 1
+			}
+		}
+	}
+	return status
+}
+
+// writeProfile writes the profile table, folded stacks and, if requested,
+// the pprof profile next to each other using opts.profile as the prefix.
+func writeProfile(opts runOptions, profiler *profile.Profiler) error {
+	outputs := map[string]func(io.Writer) error{
+		".txt":    func(w io.Writer) error { return profiler.WriteTop(w, opts.profileTop) },
+		".folded": profiler.WriteFolded,
+	}
+	if opts.pprof {
+		outputs[".pb.gz"] = profiler.WritePprof
+	}
+
+	for ext, write := range outputs {
+		if err := writeFile(opts.profile+ext, write); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCoverage(filename string, profile *coverage.Profile) error {
+	return writeFile(filename, profile.WriteJSON)
+}
+
+// writeFile creates filename and fills it using write.
+func writeFile(filename string, write func(io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
 
-func execute(command, filename string, stdout, stderr io.Writer) bool {
+// execute runs a command against a file and returns the process exit status.
+func execute(command, filename string, opts runOptions, stdout, stderr io.Writer) int {
 	if command == "tokenize" {
-		return tokenize(filename, stdout, stderr)
+		return statusOf(tokenize(filename, stdout, stderr))
 	}
 
 	if command == "parse" {
-		return parse(filename, stdout, stderr)
+		return statusOf(parse(filename, stdout, stderr))
 	}
 
 	if command == "evaluate" || command == "run" {
-		if !evaluate(filename, stdout, stderr) {
-			os.Exit(70)
-		}
-		return true
+		return evaluate(filename, opts, stdout, stderr)
 	}
 
 	fmt.Fprintf(stderr, "unknown command: %s\n", command)
-	return false
+	return 65
+}
+
+// statusOf maps the result of tokenize and parse to an exit status.
+func statusOf(ok bool) int {
+	if !ok {
+		return 65
+	}
+	return 0
 }
 
 func main() {
+	if len(os.Args) >= 2 {
+		switch os.Args[1] {
+		case "doc":
+			os.Exit(generateDocs(os.Args[2:], os.Stdout, os.Stderr))
+		case "test":
+			os.Exit(runTests(os.Args[2:], os.Stdout, os.Stderr))
+		case "cover":
+			os.Exit(renderCoverage(os.Args[2:], os.Stdout, os.Stderr))
+		case "lint":
+			os.Exit(runLint(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
 	if len(os.Args) < 3 {
 		fmt.Fprintln(os.Stderr, "Usage: ./your_program.sh tokenize <filename>")
 		os.Exit(1)
 	}
 
-	if !execute(os.Args[1], os.Args[2], os.Stdout, os.Stderr) {
-		os.Exit(65)
+	command := os.Args[1]
+
+	opts, args, ok := parseRunOptions(command, os.Args[2:], os.Stderr)
+	if !ok || len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: ./your_program.sh %s [flags] <filename>\n", command)
+		os.Exit(1)
 	}
-	os.Exit(0)
+
+	opts.scriptArgs = args[1:]
+	os.Exit(execute(command, args[0], opts, os.Stdout, os.Stderr))
 }
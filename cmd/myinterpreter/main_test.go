@@ -2,7 +2,9 @@ package main
 
 import (
 	"bytes"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -78,6 +80,65 @@ EOF  null
 			wantErr:   "",
 			setupFile: func(filename string) error { return os.WriteFile(filename, []byte(`("foo")`), 0644) },
 		},
+		{
+			name:     "unicode",
+			filename: "unicode.txt",
+			wantOutput: `IDENTIFIER café null
+EQUAL = null
+STRING "naïve" naïve
+EOF  null
+`,
+			wantErr: "[line 1] Error: Unexpected character: €\n",
+			setupFile: func(filename string) error {
+				return os.WriteFile(filename, []byte(`café = € "naïve"`), 0644)
+			},
+		},
+		{
+			name:     "unterminated comment",
+			filename: "unterminated_comment.txt",
+			wantOutput: `LEFT_PAREN ( null
+EOF  null
+`,
+			wantErr: "[line 3] Error: Unterminated comment.\n",
+			setupFile: func(filename string) error {
+				return os.WriteFile(filename, []byte("( /* a\n/* b */\n"), 0644)
+			},
+		},
+		{
+			name:     "invalid escape",
+			filename: "invalid_escape.txt",
+			wantOutput: `STRING "a\\tb" a\tb
+SEMICOLON ; null
+EOF  null
+`,
+			wantErr: "[line 2] Error: Invalid escape sequence: \\q\n",
+			setupFile: func(filename string) error {
+				return os.WriteFile(filename, []byte("\"a\\tb\";\n\"\\q\""), 0644)
+			},
+		},
+		{
+			name:     "multi-line strings",
+			filename: "multiline.txt",
+			wantOutput: `STRING """a\nb""" a\nb
+STRING "c\nd" c\nd
+EOF  null
+`,
+			wantErr: "",
+			setupFile: func(filename string) error {
+				return os.WriteFile(filename, []byte("\"\"\"a\nb\"\"\" \"c\nd\""), 0644)
+			},
+		},
+		{
+			name:     "escaped newline",
+			filename: "escaped_newline.txt",
+			wantOutput: `STRING "a\\nb\\\\n" a\nb\\n
+EOF  null
+`,
+			wantErr: "",
+			setupFile: func(filename string) error {
+				return os.WriteFile(filename, []byte(`"a\nb\\n"`), 0644)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -119,15 +180,15 @@ func TestExecuteUnknownCommand(t *testing.T) {
 	filename := "test.txt"
 
 	// Act
-	ok := execute(command, filename, stdout, stderr)
+	status := execute(command, filename, runOptions{}, stdout, stderr)
 
 	// Assert
 	expectedError := "unknown command: unknown\n"
 	if stderr.String() != expectedError {
 		t.Errorf("Expected error message %q, got %q", expectedError, stderr.String())
 	}
-	if ok {
-		t.Error("Expected run to return false for unknown command")
+	if status != 65 {
+		t.Errorf("Expected status 65 for unknown command, got %d", status)
 	}
 }
 
@@ -391,7 +452,7 @@ func TestEvaluate(t *testing.T) {
 			}
 
 			var stdout, stderr bytes.Buffer
-			ok := evaluate(tt.filename, &stdout, &stderr)
+			ok := evaluate(tt.filename, runOptions{}, &stdout, &stderr) == 0
 
 			// Check error
 			errOutput := stderr.String()
@@ -415,3 +476,383 @@ func TestEvaluate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRunOptions(t *testing.T) {
+	var stderr bytes.Buffer
+
+	opts, args, ok := parseRunOptions("run", []string{"--seed", "42", "script.lox"}, &stderr)
+	if !ok {
+		t.Fatalf("expected flags to parse, got error %q", stderr.String())
+	}
+	if !opts.seeded || opts.seed != 42 {
+		t.Errorf("expected seed 42, got %+v", opts)
+	}
+	if len(args) != 1 || args[0] != "script.lox" {
+		t.Errorf("expected remaining args [script.lox], got %v", args)
+	}
+
+	opts, args, ok = parseRunOptions("tokenize", []string{"--seed", "42"}, &stderr)
+	if !ok || opts.seeded || len(args) != 2 {
+		t.Errorf("expected tokenize to ignore flags, got %+v %v", opts, args)
+	}
+
+	if _, _, ok := parseRunOptions("run", []string{"--seed", "abc", "script.lox"}, &stderr); ok {
+		t.Errorf("expected invalid seed to fail")
+	}
+}
+
+func TestEvaluateWithSeed(t *testing.T) {
+	filename := "seeded.txt"
+	defer os.Remove(filename)
+	if err := os.WriteFile(filename, []byte(`print randomInt(1, 1000000);`), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+
+	var first, second, stderr bytes.Buffer
+	evaluate(filename, runOptions{seed: 3, seeded: true}, &first, &stderr)
+	evaluate(filename, runOptions{seed: 3, seeded: true}, &second, &stderr)
+
+	if first.String() != second.String() {
+		t.Errorf("expected identical output for the same seed, got %q and %q", first.String(), second.String())
+	}
+}
+
+func TestEvaluateDeterministic(t *testing.T) {
+	filename := "deterministic.txt"
+	defer os.Remove(filename)
+	if err := os.WriteFile(filename, []byte(`print clock(); print random() == random();`), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+
+	var first, second, stderr bytes.Buffer
+	evaluate(filename, runOptions{deterministic: true}, &first, &stderr)
+	evaluate(filename, runOptions{deterministic: true}, &second, &stderr)
+
+	if first.String() != "0\nfalse\n" {
+		t.Errorf("expected frozen clock output, got %q", first.String())
+	}
+	if first.String() != second.String() {
+		t.Errorf("expected identical output across runs, got %q and %q", first.String(), second.String())
+	}
+}
+
+func TestParseRunOptionsDeterministic(t *testing.T) {
+	var stderr bytes.Buffer
+	opts, args, ok := parseRunOptions("run", []string{"--deterministic", "script.lox"}, &stderr)
+	if !ok || !opts.deterministic || len(args) != 1 {
+		t.Errorf("expected --deterministic to parse, got %+v %v", opts, args)
+	}
+}
+
+func TestEvaluateExitStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		opts       runOptions
+		wantStatus int
+		wantOutput string
+	}{
+		{"success", `print "ok";`, runOptions{}, 0, "ok\n"},
+		{"syntax error", `print (;`, runOptions{}, 65, ""},
+		{"runtime error", `-true;`, runOptions{}, 70, ""},
+		{"exit code", `print "bye"; exit(7); print "never";`, runOptions{}, 7, "bye\n"},
+		{"script args", `print args;`, runOptions{scriptArgs: []string{"a", "b"}}, 0, "[a, b]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := "status.txt"
+			defer os.Remove(filename)
+			if err := os.WriteFile(filename, []byte(tt.source), 0644); err != nil {
+				t.Fatalf("failed to set up file: %v", err)
+			}
+
+			var stdout, stderr bytes.Buffer
+			status := evaluate(filename, tt.opts, &stdout, &stderr)
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if stdout.String() != tt.wantOutput {
+				t.Errorf("expected output %q, got %q", tt.wantOutput, stdout.String())
+			}
+		})
+	}
+}
+
+func TestGenerateDocs(t *testing.T) {
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "lib.lox")
+	if err := os.WriteFile(lib, []byte("/// Adds.\nfun add(a, b) { return a + b; }"), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+	broken := filepath.Join(dir, "broken.lox")
+	if err := os.WriteFile(broken, []byte("print (;"), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := generateDocs([]string{lib}, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected status 0, got %d: %s", status, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "### `fun add(a, b)`") {
+		t.Errorf("markdown output missing function heading:\n%s", stdout.String())
+	}
+
+	out := filepath.Join(dir, "api.html")
+	stdout.Reset()
+	if status := generateDocs([]string{"--format=html", "--out=" + out, lib}, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected status 0, got %d: %s", status, stderr.String())
+	}
+	written, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected output file: %v", err)
+	}
+	if !strings.Contains(string(written), "<p>Adds.</p>") || stdout.Len() != 0 {
+		t.Errorf("html not written to file:\n%s", written)
+	}
+
+	if status := generateDocs([]string{lib, broken}, &stdout, &stderr); status != 65 {
+		t.Errorf("expected status 65 for a parse error, got %d", status)
+	}
+	if status := generateDocs([]string{"--format=pdf", lib}, &stdout, &stderr); status != 1 {
+		t.Errorf("expected status 1 for an unknown format, got %d", status)
+	}
+	if status := generateDocs(nil, &stdout, &stderr); status != 1 {
+		t.Errorf("expected status 1 without files, got %d", status)
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	dir := t.TempDir()
+	suite := filepath.Join(dir, "math_test.lox")
+	source := `var counter = 0;
+fun testIncrements() { counter = counter + 1; assertEqual(counter, 1); }
+fun testFreshState() { assertEqual(counter, 0); }
+test "fails" {
+  print "noise";
+  assertEqual(2, 3);
+}
+fun helper() { assert(false); }`
+	if err := os.WriteFile(suite, []byte(source), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "helper.lox"), []byte("assert(false);"), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := runTests([]string{dir}, &stdout, &stderr); status != 1 {
+		t.Fatalf("expected status 1, got %d: %s", status, stderr.String())
+	}
+	for _, want := range []string{
+		"--- PASS: testIncrements",
+		"--- PASS: testFreshState",
+		"--- FAIL: fails (" + suite + ":4)",
+		suite + ":6: assertEqual failed",
+		"noise",
+		"FAIL: 1 failed, 2 passed, 3 total",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, stdout.String())
+		}
+	}
+	if strings.Contains(stdout.String(), "helper") {
+		t.Errorf("non-test function was run:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if status := runTests([]string{"--run=^test", suite}, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected status 0 with --run, got %d:\n%s", status, stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "PASS: 2 passed, 2 total") {
+		t.Errorf("unexpected summary:\n%s", stdout.String())
+	}
+
+	broken := filepath.Join(dir, "broken_test.lox")
+	if err := os.WriteFile(broken, []byte("print (;"), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+	if status := runTests([]string{broken}, &stdout, &stderr); status != 65 {
+		t.Errorf("expected status 65 for a parse error, got %d", status)
+	}
+	if status := runTests([]string{t.TempDir()}, &stdout, &stderr); status != 1 {
+		t.Errorf("expected status 1 without test files, got %d", status)
+	}
+}
+
+func TestCoverageCommands(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.lox")
+	if err := os.WriteFile(script, []byte("if (true) print \"yes\";\nfun unused() {\n  print \"no\";\n}\n"), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+	profile := filepath.Join(dir, "cover.json")
+
+	opts, args, ok := parseRunOptions("run", []string{"--coverage=" + profile, script}, io.Discard)
+	if !ok || opts.coverage != profile || len(args) != 1 {
+		t.Fatalf("unexpected options: %+v %v", opts, args)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := evaluate(script, opts, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected status 0, got %d: %s", status, stderr.String())
+	}
+
+	stdout.Reset()
+	if status := renderCoverage([]string{profile}, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected status 0, got %d: %s", status, stderr.String())
+	}
+	for _, want := range []string{
+		"total: 3/4 statements (75.0%), 1/2 branch outcomes (50.0%)",
+		"    1      1~ if (true) print \"yes\";  <- never taken: if: false",
+		"    3      0!   print \"no\";",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, stdout.String())
+		}
+	}
+
+	out := filepath.Join(dir, "cover.html")
+	if status := renderCoverage([]string{"--format=html", "--out=" + out, profile}, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected status 0, got %d: %s", status, stderr.String())
+	}
+	if written, err := os.ReadFile(out); err != nil || !strings.Contains(string(written), `class="uncovered"`) {
+		t.Errorf("html report not written: %v\n%s", err, written)
+	}
+
+	if status := renderCoverage([]string{"--format=xml", profile}, &stdout, &stderr); status != 1 {
+		t.Errorf("expected status 1 for an unknown format, got %d", status)
+	}
+	if status := renderCoverage([]string{script}, &stdout, &stderr); status != 1 {
+		t.Errorf("expected status 1 for a malformed profile, got %d", status)
+	}
+}
+
+func TestEvaluateWithProfile(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.lox")
+	if err := os.WriteFile(script, []byte("fun f() { return 1; }\nf();\nf();\nexit(3);"), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+	prefix := filepath.Join(dir, "prof")
+
+	opts, _, ok := parseRunOptions("run", []string{"--profile=" + prefix, "--profile-top=1", "--pprof", script}, io.Discard)
+	if !ok || opts.profile != prefix || opts.profileTop != 1 || !opts.pprof {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := evaluate(script, opts, &stdout, &stderr); status != 3 {
+		t.Fatalf("expected the exit status of the script, got %d: %s", status, stderr.String())
+	}
+
+	table, err := os.ReadFile(prefix + ".txt")
+	if err != nil {
+		t.Fatalf("expected a profile table: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(table)), "\n"); len(lines) != 4 {
+		t.Errorf("expected a header and 1 function row, got:\n%s", table)
+	}
+
+	folded, err := os.ReadFile(prefix + ".folded")
+	if err != nil {
+		t.Fatalf("expected folded stacks: %v", err)
+	}
+	if !strings.Contains(string(folded), "<script>;f:1 ") {
+		t.Errorf("unexpected folded stacks:\n%s", folded)
+	}
+
+	if _, err := os.Stat(prefix + ".pb.gz"); err != nil {
+		t.Errorf("expected a pprof profile: %v", err)
+	}
+}
+
+func TestEvaluateWithTrace(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.lox")
+	if err := os.WriteFile(script, []byte("fun f(x) { return x * 2; }\nprint f(4);"), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+
+	opts, _, ok := parseRunOptions("run", []string{"--trace", script}, io.Discard)
+	if !ok || !opts.trace {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+	var stdout, stderr bytes.Buffer
+	if status := evaluate(script, opts, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected status 0, got %d: %s", status, stderr.String())
+	}
+	if stdout.String() != "8\n" {
+		t.Errorf("trace leaked into stdout: %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "call f(4)\n  [line 1] return (* x 2.0);\nf returned 8\n") {
+		t.Errorf("unexpected trace:\n%s", stderr.String())
+	}
+
+	out := filepath.Join(dir, "trace.log")
+	opts, _, ok = parseRunOptions("run", []string{"--trace-out=" + out, "--trace-func=f,g", script}, io.Discard)
+	if !ok || !opts.trace || strings.Join(opts.traceFuncs, ",") != "f,g" {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+	stderr.Reset()
+	if status := evaluate(script, opts, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected status 0, got %d: %s", status, stderr.String())
+	}
+	written, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected a trace file: %v", err)
+	}
+	if string(written) != "call f(4)\n  [line 1] return (* x 2.0);\nf returned 8\n" || stderr.Len() != 0 {
+		t.Errorf("unexpected filtered trace:\n%s", written)
+	}
+}
+
+func TestRunLint(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.lox")
+	if err := os.WriteFile(script, []byte("fun f(a) {\n  var x = 1;\n  return a;\n}\nprint f(1);"), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := runLint([]string{dir}, &stdout, &stderr); status != 1 {
+		t.Fatalf("expected status 1, got %d: %s", status, stderr.String())
+	}
+	expected := script + ":2: warning: Variable 'x' is declared but never used. [unused-variable]\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q, got %q", expected, stdout.String())
+	}
+
+	stdout.Reset()
+	if status := runLint([]string{"--severity=unused-variable=info", script}, &stdout, &stderr); status != 0 {
+		t.Errorf("expected status 0 when only info findings remain, got %d", status)
+	}
+	if !strings.Contains(stdout.String(), ":2: info: ") {
+		t.Errorf("severity override not applied: %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if status := runLint([]string{"--disable=unused-variable", script}, &stdout, &stderr); status != 0 || stdout.Len() != 0 {
+		t.Errorf("expected a clean run with the rule disabled, got %d: %q", status, stdout.String())
+	}
+
+	stdout.Reset()
+	if status := runLint([]string{"--list"}, &stdout, &stderr); status != 0 || !strings.Contains(stdout.String(), "undeclared-assignment") {
+		t.Errorf("unexpected rule list (%d): %s", status, stdout.String())
+	}
+
+	if status := runLint([]string{"--disable=no-such-rule", script}, &stdout, &stderr); status != 1 {
+		t.Errorf("expected status 1 for an unknown rule, got %d", status)
+	}
+	if status := runLint([]string{"--severity=unused-variable=fatal", script}, &stdout, &stderr); status != 1 {
+		t.Errorf("expected status 1 for an unknown severity, got %d", status)
+	}
+
+	broken := filepath.Join(dir, "broken.lox")
+	if err := os.WriteFile(broken, []byte("print (;"), 0644); err != nil {
+		t.Fatalf("failed to set up file: %v", err)
+	}
+	if status := runLint([]string{broken}, &stdout, &stderr); status != 65 {
+		t.Errorf("expected status 65 for a parse error, got %d", status)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/ast"
+	"github.com/codecrafters-io/interpreter-starter-go/evaluator"
+	"github.com/codecrafters-io/interpreter-starter-go/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+	"github.com/codecrafters-io/interpreter-starter-go/parser"
+)
+
+// testCase is a top-level `test*` function or a `test "name" { }` block.
+type testCase struct {
+	name string
+	line int
+	body *ast.BlockStatement // nil for test functions
+}
+
+// runTests implements the test command. It runs every test found in the
+// given files and directories (default ".") and returns the exit status:
+// 0 when all pass, 1 when any fail and 65 when a file does not parse.
+func runTests(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	run := flags.String("run", "", "only run tests whose name matches this regular expression")
+	verbose := flags.Bool("v", false, "show the output of passing tests")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	filter, err := regexp.Compile(*run)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid --run pattern: %v\n", err)
+		return 1
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := discoverFiles(paths, "_test.lox")
+	if err != nil {
+		fmt.Fprintf(stderr, "error finding tests: %v\n", err)
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "no test files found")
+		return 1
+	}
+
+	passed, failed, status := 0, 0, 0
+	for _, filename := range files {
+		fileContents, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintf(stderr, "error reading file: %v\n", err)
+			status = 1
+			continue
+		}
+
+		p := parser.New(lexer.New(string(fileContents)))
+		program := p.ParseProgram()
+		if !p.CheckErrors(stderr) {
+			status = 65
+			continue
+		}
+
+		for _, tc := range collectTests(program) {
+			if !filter.MatchString(tc.name) {
+				continue
+			}
+
+			var output bytes.Buffer
+			failure := runTest(filename, program, tc, &output)
+			if failure == "" {
+				passed++
+				fmt.Fprintf(stdout, "--- PASS: %s (%s)\n", tc.name, filename)
+			} else {
+				failed++
+				fmt.Fprintf(stdout, "--- FAIL: %s (%s:%d)\n", tc.name, filename, tc.line)
+				fmt.Fprintln(stdout, indent(failure, "    "))
+			}
+			if output.Len() > 0 && (failure != "" || *verbose) {
+				fmt.Fprintln(stdout, "    output:")
+				fmt.Fprintln(stdout, indent(strings.TrimRight(output.String(), "\n"), "      "))
+			}
+		}
+	}
+
+	total := passed + failed
+	if failed > 0 {
+		fmt.Fprintf(stdout, "FAIL: %d failed, %d passed, %d total\n", failed, passed, total)
+		if status == 0 {
+			status = 1
+		}
+	} else {
+		fmt.Fprintf(stdout, "PASS: %d passed, %d total\n", passed, total)
+	}
+	return status
+}
+
+// discoverFiles expands directories into the files below them whose names
+// end in suffix. Files named explicitly are used as given.
+func discoverFiles(paths []string, suffix string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(name, suffix) {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// collectTests lists the tests of a program in source order.
+func collectTests(program *ast.Program) []testCase {
+	var tests []testCase
+	for _, stmt := range program.Statements {
+		switch stmt := stmt.(type) {
+		case *ast.TestStatement:
+			tests = append(tests, testCase{name: stmt.Name, line: stmt.Token.Line, body: stmt.Body})
+		case *ast.ExpressionStatement:
+			if fn, ok := stmt.Expression.(*ast.FunctionLiteral); ok && strings.HasPrefix(fn.Name.Value, "test") {
+				tests = append(tests, testCase{name: fn.Name.Value, line: fn.Token.Line})
+			}
+		}
+	}
+	return tests
+}
+
+// runTest evaluates the file in a fresh environment and then runs one test
+// in it. It returns a description of the failure, or "" if the test passed.
+func runTest(filename string, program *ast.Program, tc testCase, output io.Writer) string {
+	env := object.NewEnvironment()
+	e := evaluator.NewEvaluator(&output, &output)
+	e.ScriptPath = filename
+	e.ModulePaths = filepath.SplitList(os.Getenv("LOX_PATH"))
+
+	if failure := testFailure(filename, e.Exec(program, env)); failure != "" {
+		return "setup failed: " + failure
+	}
+
+	if tc.body != nil {
+		return testFailure(filename, e.Eval(tc.body, env))
+	}
+	fn, _ := env.Get(tc.name)
+	return testFailure(filename, e.Call(fn))
+}
+
+func testFailure(filename string, result object.Object) string {
+	switch result := result.(type) {
+	case *object.Error:
+		if result.Line > 0 {
+			return fmt.Sprintf("%s:%d: %s", filename, result.Line, result.Message)
+		}
+		return result.Message
+	case *object.Exit:
+		return fmt.Sprintf("exit() called with code %d", result.Code)
+	}
+	return ""
+}
+
+func indent(text, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
@@ -0,0 +1,131 @@
+// Package coverage records which statements and branches of a Lox program
+// execute, and renders the result as an annotated report.
+package coverage
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/codecrafters-io/interpreter-starter-go/ast"
+)
+
+// Profile holds execution counts for every registered file. Counters are
+// keyed by AST node, so statements sharing a line are counted separately.
+type Profile struct {
+	Files []*File `json:"files"`
+
+	statements map[ast.Node]*Statement
+	branches   map[ast.Node]*Branch
+}
+
+// File is the coverage of one source file.
+type File struct {
+	Path       string       `json:"path"`
+	Statements []*Statement `json:"statements"`
+	Branches   []*Branch    `json:"branches"`
+}
+
+// Statement counts how often a statement started executing.
+type Statement struct {
+	Line  int `json:"line"`
+	Count int `json:"count"`
+}
+
+// Branch counts the outcomes of a condition: an if, while or for condition,
+// the left operand of and/or, or the condition of ?:.
+type Branch struct {
+	Line  int    `json:"line"`
+	Kind  string `json:"kind"`
+	True  int    `json:"true"`
+	False int    `json:"false"`
+}
+
+// New returns an empty profile.
+func New() *Profile {
+	return &Profile{
+		statements: map[ast.Node]*Statement{},
+		branches:   map[ast.Node]*Branch{},
+	}
+}
+
+// Register adds every statement and branch of program to the profile, so
+// code that never runs is reported with a count of zero.
+func (p *Profile) Register(path string, program *ast.Program) {
+	file := &File{Path: path, Statements: []*Statement{}, Branches: []*Branch{}}
+	ast.Inspect(program, func(node ast.Node) bool {
+		if line := ast.StatementLine(node); line > 0 {
+			stmt := &Statement{Line: line}
+			p.statements[node] = stmt
+			file.Statements = append(file.Statements, stmt)
+		}
+		if kind, line := branchOf(node); kind != "" {
+			branch := &Branch{Line: line, Kind: kind}
+			p.branches[node] = branch
+			file.Branches = append(file.Branches, branch)
+		}
+		return true
+	})
+
+	sort.SliceStable(file.Statements, func(i, j int) bool { return file.Statements[i].Line < file.Statements[j].Line })
+	sort.SliceStable(file.Branches, func(i, j int) bool { return file.Branches[i].Line < file.Branches[j].Line })
+	p.Files = append(p.Files, file)
+}
+
+// branchOf returns the kind and line of a node that branches, or "" if it
+// does not.
+func branchOf(node ast.Node) (string, int) {
+	switch node := node.(type) {
+	case *ast.IfStatement:
+		return "if", node.Token.Line
+	case *ast.WhileStatement:
+		return "while", node.Token.Line
+	case *ast.ForStatement:
+		if node.Condition != nil {
+			return "for", node.Token.Line
+		}
+	case *ast.InfixExpression:
+		if node.Operator == "and" || node.Operator == "or" || node.Operator == "?:" {
+			return node.Operator, node.Token.Line
+		}
+	case *ast.ConditionalExpression:
+		return "conditional", node.Token.Line
+	}
+	return "", 0
+}
+
+// Statement counts an execution of node if it is a registered statement.
+func (p *Profile) Statement(node ast.Node) {
+	if stmt, ok := p.statements[node]; ok {
+		stmt.Count++
+	}
+}
+
+// Branch counts an outcome of the condition of node.
+func (p *Profile) Branch(node ast.Node, taken bool) {
+	branch, ok := p.branches[node]
+	if !ok {
+		return
+	}
+	if taken {
+		branch.True++
+	} else {
+		branch.False++
+	}
+}
+
+// WriteJSON writes the profile in the format read by Read.
+func (p *Profile) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// Read decodes a profile written by WriteJSON.
+func Read(r io.Reader) (*Profile, error) {
+	p := New()
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,167 @@
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/interpreter-starter-go/ast"
+	"github.com/codecrafters-io/interpreter-starter-go/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/parser"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser has errors: %v", p.Errors())
+	}
+	return program
+}
+
+const source = `var x = 1;
+if (x > 0 and x < 2) print "one"; else print "other";
+while (false) {}
+for (;;) {}
+`
+
+func TestRegister(t *testing.T) {
+	p := New()
+	p.Register("main.lox", parse(t, source))
+	file := p.Files[0]
+
+	var lines []int
+	for _, stmt := range file.Statements {
+		lines = append(lines, stmt.Line)
+	}
+	if got := fmt.Sprint(lines); got != "[1 2 2 2 3 4]" {
+		t.Errorf("unexpected statement lines: %s", got)
+	}
+
+	var kinds []string
+	for _, branch := range file.Branches {
+		kinds = append(kinds, fmt.Sprintf("%s@%d", branch.Kind, branch.Line))
+	}
+	if got := strings.Join(kinds, " "); got != "if@2 and@2 while@3" {
+		t.Errorf("unexpected branches: %s", got)
+	}
+}
+
+func TestRegisterLogicalOperators(t *testing.T) {
+	p := New()
+	p.Register("main.lox", parse(t, "var a = nil ?: 1;\nprint a or 2;\nprint a ?? 3;\nprint a ? 4 : 5;"))
+
+	var kinds []string
+	for _, branch := range p.Files[0].Branches {
+		kinds = append(kinds, fmt.Sprintf("%s@%d", branch.Kind, branch.Line))
+	}
+	if got := strings.Join(kinds, " "); got != "?:@1 or@2 conditional@4" {
+		t.Errorf("unexpected branches: %s", got)
+	}
+}
+
+func TestCounting(t *testing.T) {
+	program := parse(t, source)
+	p := New()
+	p.Register("main.lox", program)
+
+	ifStmt := program.Statements[1].(*ast.IfStatement)
+	p.Statement(program.Statements[0])
+	p.Statement(ifStmt)
+	p.Statement(ifStmt)
+	p.Statement(&ast.Nil{}) // unregistered nodes are ignored
+	p.Branch(ifStmt, true)
+	p.Branch(ifStmt, false)
+	p.Branch(ifStmt.Condition, true)
+
+	file := p.Files[0]
+	if file.Statements[0].Count != 1 || file.Statements[1].Count != 2 {
+		t.Errorf("unexpected statement counts: %d, %d", file.Statements[0].Count, file.Statements[1].Count)
+	}
+	if branch := file.Branches[0]; branch.True != 1 || branch.False != 1 {
+		t.Errorf("unexpected if outcomes: %+v", branch)
+	}
+	if branch := file.Branches[1]; branch.True != 1 || branch.False != 0 {
+		t.Errorf("unexpected and outcomes: %+v", branch)
+	}
+
+	s := p.Summarize()
+	if s.String() != "2/6 statements (33.3%), 3/6 branch outcomes (50.0%)" {
+		t.Errorf("unexpected summary: %s", s)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	p := New()
+	p.Register("main.lox", parse(t, source))
+	p.Files[0].Statements[0].Count = 3
+
+	var buf bytes.Buffer
+	if err := p.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(read.Files) != 1 || read.Files[0].Path != "main.lox" || read.Files[0].Statements[0].Count != 3 {
+		t.Errorf("profile did not round-trip: %+v", read.Files)
+	}
+	if read.Summarize() != p.Summarize() {
+		t.Errorf("expected summary %s, got %s", p.Summarize(), read.Summarize())
+	}
+}
+
+func TestText(t *testing.T) {
+	program := parse(t, source)
+	p := New()
+	p.Register("main.lox", program)
+	p.Register("gone.lox", parse(t, "print 1;"))
+
+	ifStmt := program.Statements[1].(*ast.IfStatement)
+	p.Statement(program.Statements[0])
+	p.Statement(ifStmt)
+	p.Statement(ifStmt.Consequence)
+	p.Branch(ifStmt, true)
+
+	var out bytes.Buffer
+	if err := Text(&out, p, map[string]string{"main.lox": source}); err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+
+	expected := `total: 3/7 statements (42.9%), 1/6 branch outcomes (16.7%)
+
+main.lox: 3/6 statements (50.0%), 1/6 branch outcomes (16.7%)
+    1      1  var x = 1;
+    2      1~ if (x > 0 and x < 2) print "one"; else print "other";  <- never taken: if: false, and: true, and: false
+    3      0! while (false) {}
+    4      0! for (;;) {}
+
+gone.lox: 0/1 statements (0.0%), 0/0 branch outcomes (100.0%)
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestHTML(t *testing.T) {
+	program := parse(t, source)
+	p := New()
+	p.Register("a<b>.lox", program)
+	p.Statement(program.Statements[0])
+
+	var out bytes.Buffer
+	if err := HTML(&out, p, map[string]string{"a<b>.lox": source}); err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+	for _, want := range []string{
+		"<h2>a&lt;b&gt;.lox</h2>",
+		`<span class="covered"><span class="count">1</span>var x = 1;</span>`,
+		`<span class="uncovered"><span class="count">0</span>if (x &gt; 0 and x &lt; 2)`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("HTML missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package coverage
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Summary is the fraction of statements and branch outcomes that executed.
+// Each branch has two outcomes: its condition was truthy, or it was not.
+type Summary struct {
+	Statements, StatementsHit int
+	Outcomes, OutcomesHit     int
+}
+
+func (s *Summary) add(f *File) {
+	for _, stmt := range f.Statements {
+		s.Statements++
+		if stmt.Count > 0 {
+			s.StatementsHit++
+		}
+	}
+	for _, branch := range f.Branches {
+		s.Outcomes += 2
+		if branch.True > 0 {
+			s.OutcomesHit++
+		}
+		if branch.False > 0 {
+			s.OutcomesHit++
+		}
+	}
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d/%d statements (%s), %d/%d branch outcomes (%s)",
+		s.StatementsHit, s.Statements, percent(s.StatementsHit, s.Statements),
+		s.OutcomesHit, s.Outcomes, percent(s.OutcomesHit, s.Outcomes))
+}
+
+func percent(hit, total int) string {
+	if total == 0 {
+		return "100.0%"
+	}
+	return strconv.FormatFloat(100*float64(hit)/float64(total), 'f', 1, 64) + "%"
+}
+
+// Summarize returns the totals of the whole profile.
+func (p *Profile) Summarize() Summary {
+	var s Summary
+	for _, f := range p.Files {
+		s.add(f)
+	}
+	return s
+}
+
+// line is the coverage of one source line.
+type line struct {
+	text     string
+	count    int      // highest count of the statements starting on the line
+	executes bool     // whether any statement starts on the line
+	skipped  bool     // whether some statement on the line never ran
+	missed   []string // branch outcomes never taken, e.g. "if: false"
+}
+
+// annotate pairs each line of source with the counters that start on it.
+func annotate(f *File, source string) []line {
+	lines := []line{}
+	for _, text := range strings.Split(strings.TrimSuffix(source, "\n"), "\n") {
+		lines = append(lines, line{text: text})
+	}
+
+	for _, stmt := range f.Statements {
+		if stmt.Line < 1 || stmt.Line > len(lines) {
+			continue
+		}
+		l := &lines[stmt.Line-1]
+		if stmt.Count > l.count {
+			l.count = stmt.Count
+		}
+		if stmt.Count == 0 {
+			l.skipped = true
+		}
+		l.executes = true
+	}
+	for _, branch := range f.Branches {
+		if branch.Line < 1 || branch.Line > len(lines) {
+			continue
+		}
+		l := &lines[branch.Line-1]
+		if l.executes && l.count == 0 {
+			continue // the whole line is already reported as not run
+		}
+		if branch.True == 0 {
+			l.missed = append(l.missed, branch.Kind+": true")
+		}
+		if branch.False == 0 {
+			l.missed = append(l.missed, branch.Kind+": false")
+		}
+	}
+	return lines
+}
+
+// status classifies a line as "covered", "uncovered" or "partial", or ""
+// when nothing starts on it.
+func (l line) status() string {
+	switch {
+	case l.executes && l.count == 0:
+		return "uncovered"
+	case l.skipped || len(l.missed) > 0:
+		return "partial"
+	case l.executes:
+		return "covered"
+	}
+	return ""
+}
+
+// Text writes a plain-text report: a summary per file followed by its source
+// with execution counts. Lines marked ! never ran; lines marked ~ ran only in
+// part or have a branch outcome that was never taken. sources maps each path
+// to its text; files without source are summarized only.
+func Text(w io.Writer, p *Profile, sources map[string]string) error {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "total: %s\n", p.Summarize())
+	for _, f := range p.Files {
+		var s Summary
+		s.add(f)
+		fmt.Fprintf(&out, "\n%s: %s\n", f.Path, s)
+
+		source, ok := sources[f.Path]
+		if !ok {
+			continue
+		}
+		for i, l := range annotate(f, source) {
+			count, mark := "", " "
+			if l.executes {
+				count = strconv.Itoa(l.count)
+			}
+			switch l.status() {
+			case "uncovered":
+				mark = "!"
+			case "partial":
+				mark = "~"
+			}
+			text := l.text
+			if len(l.missed) > 0 {
+				text += "  <- never taken: " + strings.Join(l.missed, ", ")
+			}
+			fmt.Fprintf(&out, "%5d %6s%s %s\n", i+1, count, mark, text)
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// HTML writes the report as a self-contained HTML page.
+func HTML(w io.Writer, p *Profile, sources map[string]string) error {
+	var out strings.Builder
+
+	out.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Coverage Report</title>\n<style>\n" +
+		".covered { background: #dfd; }\n.uncovered { background: #fdd; }\n.partial { background: #ffd; }\n" +
+		".count { color: #888; display: inline-block; width: 5em; text-align: right; margin-right: 1em; }\n" +
+		"</style>\n</head>\n<body>\n<h1>Coverage Report</h1>\n")
+	fmt.Fprintf(&out, "<p>Total: %s</p>\n", html.EscapeString(p.Summarize().String()))
+
+	for _, f := range p.Files {
+		var s Summary
+		s.add(f)
+		fmt.Fprintf(&out, "<h2>%s</h2>\n<p>%s</p>\n", html.EscapeString(f.Path), html.EscapeString(s.String()))
+
+		source, ok := sources[f.Path]
+		if !ok {
+			continue
+		}
+		out.WriteString("<pre>\n")
+		for _, l := range annotate(f, source) {
+			count := ""
+			if l.executes {
+				count = strconv.Itoa(l.count)
+			}
+			attrs := ""
+			if status := l.status(); status != "" {
+				attrs = fmt.Sprintf(" class=\"%s\"", status)
+			}
+			if len(l.missed) > 0 {
+				attrs += fmt.Sprintf(" title=\"never taken: %s\"", html.EscapeString(strings.Join(l.missed, ", ")))
+			}
+			fmt.Fprintf(&out, "<span%s><span class=\"count\">%s</span>%s</span>\n", attrs, count, html.EscapeString(l.text))
+		}
+		out.WriteString("</pre>\n")
+	}
+	out.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
@@ -0,0 +1,68 @@
+// Package doc extracts API documentation from parsed Lox programs and
+// renders it as Markdown or HTML.
+package doc
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/ast"
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+const (
+	FunctionSymbol = "function"
+	VariableSymbol = "variable"
+)
+
+// Symbol is a documented top-level declaration.
+type Symbol struct {
+	Kind   string
+	Name   string
+	Params []string // function parameters, in order
+	Doc    string   // the attached `///` comment
+	Line   int
+}
+
+// Signature returns the declaration as it reads in source, e.g. "fun add(a, b)".
+func (s Symbol) Signature() string {
+	if s.Kind == FunctionSymbol {
+		return "fun " + s.Name + "(" + strings.Join(s.Params, ", ") + ")"
+	}
+	return "var " + s.Name
+}
+
+// File holds the exported symbols of one source file in declaration order.
+type File struct {
+	Path    string
+	Symbols []Symbol
+}
+
+// Collect gathers the top-level functions and variables of a program.
+// Names that modules do not export are left out.
+func Collect(path string, program *ast.Program) File {
+	file := File{Path: path}
+
+	for _, stmt := range program.Statements {
+		var symbol Symbol
+		switch stmt := stmt.(type) {
+		case *ast.VarStatement:
+			symbol = Symbol{Kind: VariableSymbol, Name: stmt.Name.Value, Doc: stmt.Doc, Line: stmt.Token.Line}
+		case *ast.ExpressionStatement:
+			fn, ok := stmt.Expression.(*ast.FunctionLiteral)
+			if !ok {
+				continue
+			}
+			symbol = Symbol{Kind: FunctionSymbol, Name: fn.Name.Value, Doc: fn.Doc, Line: fn.Token.Line}
+			for _, param := range fn.Parameters {
+				symbol.Params = append(symbol.Params, param.Value)
+			}
+		default:
+			continue
+		}
+
+		if object.IsExported(symbol.Name) {
+			file.Symbols = append(file.Symbols, symbol)
+		}
+	}
+	return file
+}
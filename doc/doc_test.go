@@ -0,0 +1,126 @@
+package doc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/interpreter-starter-go/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/parser"
+)
+
+func collect(t *testing.T, path, input string) File {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser has errors: %v", p.Errors())
+	}
+	return Collect(path, program)
+}
+
+func TestCollect(t *testing.T) {
+	file := collect(t, "lib.lox", `
+/// The answer.
+var answer = 42;
+
+/// Adds two numbers.
+fun add(a, b) { return a + b; }
+
+fun undocumented() {}
+fun _private() {}
+var _hidden = 1;
+print add(1, 2);
+`)
+
+	expected := []Symbol{
+		{Kind: VariableSymbol, Name: "answer", Doc: "The answer.", Line: 3},
+		{Kind: FunctionSymbol, Name: "add", Params: []string{"a", "b"}, Doc: "Adds two numbers.", Line: 6},
+		{Kind: FunctionSymbol, Name: "undocumented", Line: 8},
+	}
+
+	if len(file.Symbols) != len(expected) {
+		t.Fatalf("wrong number of symbols. expected=%d, got=%d", len(expected), len(file.Symbols))
+	}
+	for i, want := range expected {
+		got := file.Symbols[i]
+		if got.Kind != want.Kind || got.Name != want.Name || got.Doc != want.Doc || got.Line != want.Line ||
+			strings.Join(got.Params, ",") != strings.Join(want.Params, ",") {
+			t.Errorf("symbols[%d] wrong. expected=%+v, got=%+v", i, want, got)
+		}
+	}
+
+	if sig := file.Symbols[1].Signature(); sig != "fun add(a, b)" {
+		t.Errorf("wrong signature. got=%q", sig)
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	tests := []struct {
+		path, name, expected string
+	}{
+		{"lib.lox", "add", "lib-lox-add"},
+		{"./src/My Lib.lox", "to_s", "src-my-lib-lox-to_s"},
+	}
+
+	for _, tt := range tests {
+		if got := Anchor(tt.path, tt.name); got != tt.expected {
+			t.Errorf("Anchor(%q, %q) wrong. expected=%q, got=%q", tt.path, tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	files := []File{
+		collect(t, "math.lox", "/// Squares `x`.\n///\n/// See `cube()`.\nfun square(x) { return x * x; }\n/// Cubes.\nfun cube(x) {}"),
+		collect(t, "greet.lox", "/// Uses `square` and `missing`.\nfun greet() {}"),
+	}
+
+	var out bytes.Buffer
+	if err := Markdown(&out, files); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "# API Reference\n" +
+		"\n## math.lox\n\n" +
+		"- [`fun square(x)`](#math-lox-square)\n" +
+		"- [`fun cube(x)`](#math-lox-cube)\n" +
+		"\n<a id=\"math-lox-square\"></a>\n### `fun square(x)`\n\n" +
+		"Squares `x`.\n\n" +
+		"See [`cube()`](#math-lox-cube).\n\n" +
+		"*Defined in math.lox on line 4.*\n" +
+		"\n<a id=\"math-lox-cube\"></a>\n### `fun cube(x)`\n\n" +
+		"Cubes.\n\n" +
+		"*Defined in math.lox on line 6.*\n" +
+		"\n## greet.lox\n\n" +
+		"- [`fun greet()`](#greet-lox-greet)\n" +
+		"\n<a id=\"greet-lox-greet\"></a>\n### `fun greet()`\n\n" +
+		"Uses [`square`](#math-lox-square) and `missing`.\n\n" +
+		"*Defined in greet.lox on line 2.*\n"
+
+	if out.String() != expected {
+		t.Errorf("markdown wrong.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestHTML(t *testing.T) {
+	files := []File{
+		collect(t, "a<b>.lox", "/// Compares `a < b`, see `other`.\nfun less(a, b) { return a < b; }\nvar other = 1;"),
+	}
+
+	var out bytes.Buffer
+	if err := HTML(&out, files); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"<h2>a&lt;b&gt;.lox</h2>",
+		`<li><a href="#a-b-lox-less"><code>fun less(a, b)</code></a></li>`,
+		`<h3 id="a-b-lox-other"><code>var other</code></h3>`,
+		`<p>Compares <code>a &lt; b</code>, see <a href="#a-b-lox-other"><code>other</code></a>.</p>`,
+		"<p><em>Defined in a&lt;b&gt;.lox on line 2.</em></p>",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("html missing %q in:\n%s", want, out.String())
+		}
+	}
+}
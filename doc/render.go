@@ -0,0 +1,138 @@
+package doc
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// codeSpan matches a backquoted span in doc text. Spans naming a documented
+// symbol, optionally followed by "()", become links to it.
+var codeSpan = regexp.MustCompile("`([^`\\n]+)`")
+
+// Anchor returns the fragment identifier of a symbol, unique across files.
+func Anchor(path, name string) string {
+	var out strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(path + "-" + name) {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127 {
+			out.WriteRune(r)
+			dash = false
+		} else if !dash {
+			out.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.Trim(out.String(), "-")
+}
+
+// linker resolves names mentioned in doc text to symbol anchors, preferring
+// the file the text belongs to.
+type linker struct {
+	files []File
+}
+
+func (l linker) resolve(from File, span string) (string, bool) {
+	name := strings.TrimSuffix(codeSpan.FindStringSubmatch(span)[1], "()")
+	for _, symbol := range from.Symbols {
+		if symbol.Name == name {
+			return Anchor(from.Path, name), true
+		}
+	}
+	for _, file := range l.files {
+		for _, symbol := range file.Symbols {
+			if symbol.Name == name {
+				return Anchor(file.Path, name), true
+			}
+		}
+	}
+	return "", false
+}
+
+// paragraphs splits doc text on blank lines.
+func paragraphs(text string) []string {
+	var out []string
+	for _, para := range strings.Split(text, "\n\n") {
+		if para = strings.TrimSpace(para); para != "" {
+			out = append(out, para)
+		}
+	}
+	return out
+}
+
+// Markdown writes the documentation of files as a single Markdown page.
+func Markdown(w io.Writer, files []File) error {
+	links := linker{files: files}
+	var out strings.Builder
+
+	out.WriteString("# API Reference\n")
+	for _, file := range files {
+		fmt.Fprintf(&out, "\n## %s\n\n", file.Path)
+		if len(file.Symbols) == 0 {
+			out.WriteString("No exported declarations.\n")
+			continue
+		}
+		for _, symbol := range file.Symbols {
+			fmt.Fprintf(&out, "- [`%s`](#%s)\n", symbol.Signature(), Anchor(file.Path, symbol.Name))
+		}
+
+		for _, symbol := range file.Symbols {
+			fmt.Fprintf(&out, "\n<a id=\"%s\"></a>\n### `%s`\n\n", Anchor(file.Path, symbol.Name), symbol.Signature())
+			for _, para := range paragraphs(symbol.Doc) {
+				text := codeSpan.ReplaceAllStringFunc(para, func(span string) string {
+					if anchor, ok := links.resolve(file, span); ok {
+						return fmt.Sprintf("[%s](#%s)", span, anchor)
+					}
+					return span
+				})
+				out.WriteString(text + "\n\n")
+			}
+			fmt.Fprintf(&out, "*Defined in %s on line %d.*\n", file.Path, symbol.Line)
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// HTML writes the documentation of files as a self-contained HTML page.
+func HTML(w io.Writer, files []File) error {
+	links := linker{files: files}
+	var out strings.Builder
+
+	out.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>API Reference</title>\n</head>\n<body>\n<h1>API Reference</h1>\n")
+	for _, file := range files {
+		fmt.Fprintf(&out, "<h2>%s</h2>\n", html.EscapeString(file.Path))
+		if len(file.Symbols) == 0 {
+			out.WriteString("<p>No exported declarations.</p>\n")
+			continue
+		}
+
+		out.WriteString("<ul>\n")
+		for _, symbol := range file.Symbols {
+			fmt.Fprintf(&out, "<li><a href=\"#%s\"><code>%s</code></a></li>\n", Anchor(file.Path, symbol.Name), html.EscapeString(symbol.Signature()))
+		}
+		out.WriteString("</ul>\n")
+
+		for _, symbol := range file.Symbols {
+			fmt.Fprintf(&out, "<h3 id=\"%s\"><code>%s</code></h3>\n", Anchor(file.Path, symbol.Name), html.EscapeString(symbol.Signature()))
+			for _, para := range paragraphs(symbol.Doc) {
+				text := codeSpan.ReplaceAllStringFunc(html.EscapeString(para), func(span string) string {
+					code := "<code>" + strings.Trim(span, "`") + "</code>"
+					if anchor, ok := links.resolve(file, span); ok {
+						return fmt.Sprintf("<a href=\"#%s\">%s</a>", anchor, code)
+					}
+					return code
+				})
+				fmt.Fprintf(&out, "<p>%s</p>\n", text)
+			}
+			fmt.Fprintf(&out, "<p><em>Defined in %s on line %d.</em></p>\n", html.EscapeString(file.Path), symbol.Line)
+		}
+	}
+	out.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
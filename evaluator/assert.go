@@ -0,0 +1,149 @@
+package evaluator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+// assertionFailure builds the error raised by a failed assertion, appending
+// the optional user message to the headline.
+func assertionFailure(headline string, message []object.Object, details ...string) *object.Error {
+	if len(message) > 0 {
+		headline += ": " + message[0].Inspect()
+	}
+	return newError("%s", strings.Join(append([]string{headline}, details...), "\n"))
+}
+
+// formatValue renders a value for a failure message, quoting strings, also
+// inside lists and maps, so that type and whitespace differences are visible.
+func formatValue(obj object.Object) string {
+	switch obj := obj.(type) {
+	case nil:
+		return NIL.Inspect()
+	case *object.String:
+		return strconv.Quote(obj.Value)
+	case *object.List:
+		elements := make([]string, len(obj.Elements))
+		for i, el := range obj.Elements {
+			elements[i] = formatValue(el)
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *object.Map:
+		pairs := make([]string, len(obj.Keys))
+		for i, key := range obj.Keys {
+			pairs[i] = strconv.Quote(key) + ": " + formatValue(obj.Pairs[key])
+		}
+		return "{" + strings.Join(pairs, ", ") + "}"
+	}
+	return obj.Inspect()
+}
+
+// diffLines renders a line diff of two multi-line strings, marking lines
+// only in expected with "-" and lines only in actual with "+".
+func diffLines(expected, actual string) []string {
+	a, b := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	out := []string{"  --- expected", "  +++ actual"}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out = append(out, "    "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, "  - "+a[i])
+			i++
+		default:
+			out = append(out, "  + "+b[j])
+			j++
+		}
+	}
+	return out
+}
+
+func (e *Evaluator) assertBuiltins() map[string]*object.NativeFunction {
+	return map[string]*object.NativeFunction{
+		"assert": {
+			Fn: func(args ...object.Object) object.Object {
+				if len(args) < 1 || len(args) > 2 {
+					return newError("Expected 1 or 2 arguments but got %d.", len(args))
+				}
+				if !isTruthy(args[0]) {
+					return assertionFailure("Assertion failed", args[1:])
+				}
+				return NIL
+			},
+		},
+		"assertEqual": {
+			Fn: func(args ...object.Object) object.Object {
+				if len(args) < 2 || len(args) > 3 {
+					return newError("Expected 2 or 3 arguments but got %d.", len(args))
+				}
+				for i, arg := range args {
+					if arg == nil { // a call to a function that returned nothing
+						args[i] = NIL
+					}
+				}
+				actual, expected := args[0], args[1]
+				if objectsEqual(actual, expected) {
+					return NIL
+				}
+
+				a, aok := actual.(*object.String)
+				x, xok := expected.(*object.String)
+				if aok && xok && (strings.Contains(a.Value, "\n") || strings.Contains(x.Value, "\n")) {
+					return assertionFailure("assertEqual failed", args[2:], diffLines(x.Value, a.Value)...)
+				}
+				return assertionFailure("assertEqual failed", args[2:],
+					"  expected: "+formatValue(expected),
+					"  actual:   "+formatValue(actual))
+			},
+		},
+		"assertThrows": {
+			Fn: func(args ...object.Object) object.Object {
+				if len(args) < 1 || len(args) > 2 {
+					return newError("Expected 1 or 2 arguments but got %d.", len(args))
+				}
+				switch args[0].(type) {
+				case *object.Function, *object.NativeFunction:
+				default:
+					return newError("assertThrows() expects argument 1 to be a function.")
+				}
+
+				result := e.Call(args[0])
+				if exit, ok := result.(*object.Exit); ok {
+					return exit
+				}
+				err, ok := result.(*object.Error)
+				if !ok {
+					return newError("assertThrows failed: expected an error but none was thrown.")
+				}
+				if len(args) == 2 {
+					want := args[1].Inspect()
+					if err.Message != want {
+						return newError("assertThrows failed: expected error %s but got %s.", strconv.Quote(want), strconv.Quote(err.Message))
+					}
+				}
+				return &object.ErrorValue{Error: err}
+			},
+		},
+	}
+}
@@ -1,12 +1,20 @@
 package evaluator
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/codecrafters-io/interpreter-starter-go/ast"
+	"github.com/codecrafters-io/interpreter-starter-go/coverage"
 	"github.com/codecrafters-io/interpreter-starter-go/object"
+	"github.com/codecrafters-io/interpreter-starter-go/profile"
 )
 
 var (
@@ -15,28 +23,86 @@ var (
 	FALSE = &object.Boolean{Value: false}
 )
 
-var builtins = map[string]*object.NativeFunction{
-	"clock": {
-		Fn: func(args ...object.Object) object.Object {
-			if len(args) != 0 {
-				return newError("clock() takes no arguments")
-			}
-			seconds := float64(time.Now().Unix())
-			return &object.Number{Value: seconds}
-		},
-	},
-}
+// builtins are natives shared by every evaluator. Each group of natives
+// registers itself here from an init function.
+var builtins = map[string]object.Object{}
 
 type Evaluator struct {
 	stdout io.Writer
 	stderr io.Writer
+
+	// ScriptPath is the file being evaluated. Relative imports are resolved
+	// against the importing file's directory first, then against each of
+	// ModulePaths.
+	ScriptPath  string
+	ModulePaths []string
+
+	modules     map[string]*object.Module // evaluated modules by absolute path
+	importStack []string                  // modules currently being evaluated
+
+	// natives are builtins bound to this evaluator, such as the random
+	// number generator whose state must not be shared between interpreters.
+	natives map[string]object.Object
+	rand    *rand.Rand
+
+	// Stdin is read by the input and readLine natives.
+	Stdin io.Reader
+	stdin *bufio.Reader
+
+	// Clock is the time source of the clock native. It defaults to
+	// time.Now and can be replaced to make scripts deterministic.
+	Clock func() time.Time
+
+	// Coverage, when set, counts the statements and branches executed in
+	// the files registered with it.
+	Coverage *coverage.Profile
+
+	// Profiler, when set, is told about every call to a user function.
+	Profiler *profile.Profiler
+
+	// Tracer, when set, logs statements, calls and assignments.
+	Tracer *Tracer
+
+	line   int     // line of the statement being evaluated
+	frames []frame // active user function calls, innermost last
+}
+
+// frame records a user function call so runtime errors can report a stack.
+type frame struct {
+	name     string
+	callLine int
 }
 
 func NewEvaluator(stdout, stderr *io.Writer) *Evaluator {
-	return &Evaluator{stdout: *stdout, stderr: *stderr}
+	e := &Evaluator{
+		stdout:  *stdout,
+		stderr:  *stderr,
+		modules: make(map[string]*object.Module),
+		natives: make(map[string]object.Object),
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		Clock:   time.Now,
+		Stdin:   os.Stdin,
+	}
+	for _, group := range []map[string]*object.NativeFunction{e.timeBuiltins(), e.randomBuiltins(), e.processBuiltins(), e.assertBuiltins()} {
+		for name, fn := range group {
+			e.natives[name] = fn
+		}
+	}
+	e.SetArgs(nil)
+	return e
 }
 
 func (e *Evaluator) Eval(node ast.Node, env *object.Environment) object.Object {
+	if line := ast.StatementLine(node); line > 0 {
+		e.line = line
+		if e.Coverage != nil {
+			e.Coverage.Statement(node)
+		}
+		if e.Tracer != nil {
+			e.traceStatement(node, line)
+		}
+	}
+
 	switch node := node.(type) {
 	case *ast.Program:
 		return e.evalProgram(node.Statements, env)
@@ -51,6 +117,8 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment) object.Object {
 		return NIL
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
+	case *ast.InterpolatedString:
+		return e.evalInterpolatedString(node, env)
 	case *ast.NumberLiteral:
 		return &object.Number{Value: node.Value}
 	case *ast.GroupExpression:
@@ -61,13 +129,19 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment) object.Object {
 		return result
 	case *ast.PrefixExpression:
 		right := e.Eval(node.Right, env)
+		if isError(right) {
+			return right
+		}
 		return evalPrefixExpression(node.Operator, right)
 	case *ast.InfixExpression:
-		if node.Operator == "or" {
-			return e.evalOrExpression(node.Left, node.Right, env)
+		if node.Operator == "or" || node.Operator == "?:" {
+			return e.evalOrExpression(node, env)
+		}
+		if node.Operator == "??" {
+			return e.evalCoalesceExpression(node.Left, node.Right, env)
 		}
 		if node.Operator == "and" {
-			return e.evalAndExpression(node.Left, node.Right, env)
+			return e.evalAndExpression(node, env)
 		}
 
 		left := e.Eval(node.Left, env)
@@ -79,6 +153,16 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment) object.Object {
 			return right
 		}
 		return evalInfixExpression(node.Operator, left, right)
+	case *ast.ConditionalExpression:
+		condition := e.Eval(node.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		e.coverBranch(node, isTruthy(condition))
+		if isTruthy(condition) {
+			return e.Eval(node.Consequence, env)
+		}
+		return e.Eval(node.Alternative, env)
 	case *ast.PrintExpression:
 		value := e.Eval(node.Expression, env)
 		if isError(value) {
@@ -96,8 +180,22 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(value) {
 			return value
 		}
-		env.Assign(node.Name.Value, value)
+		e.traceAssign(node.Name.Value, value, env.Assign(node.Name.Value, value))
 		return value
+	case *ast.IndexAssignExpression:
+		ref := e.evalReference(node.Target, env)
+		if ref.err != nil {
+			return ref.err
+		}
+		value := e.Eval(node.Value, env)
+		if isError(value) {
+			return value
+		}
+		return ref.store(value)
+	case *ast.CompoundAssignExpression:
+		return e.evalCompoundAssignExpression(node, env)
+	case *ast.UpdateExpression:
+		return e.evalUpdateExpression(node, env)
 	case *ast.Identifier:
 		return e.evalIdentifier(node, env)
 	case *ast.VarStatement:
@@ -106,12 +204,14 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment) object.Object {
 			return value
 		}
 		env.Define(node.Name.Value, value)
+		e.traceAssign("var "+node.Name.Value, value, nil)
 		return nil
 	case *ast.IfStatement:
 		condition := e.Eval(node.Condition, env)
 		if isError(condition) {
 			return condition
 		}
+		e.coverBranch(node, isTruthy(condition))
 		if isTruthy(condition) {
 			return e.Eval(node.Consequence, env)
 		}
@@ -120,27 +220,28 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 		return nil
 	case *ast.WhileStatement:
-		for isTruthy(e.Eval(node.Condition, env)) {
+		for {
+			condition := e.Eval(node.Condition, env)
+			if isError(condition) {
+				return condition
+			}
+			e.coverBranch(node, isTruthy(condition))
+			if !isTruthy(condition) {
+				break
+			}
 			result := e.Eval(node.Consequence, env)
-			if result != nil && result.Type() == object.RETURN_VALUE_OBJ {
+			if isReturnOrError(result) {
 				return result
 			}
 		}
 		return nil
 	case *ast.ForStatement:
-		enclosedEnv := object.NewEnclosedEnvironment(env)
-		e.Eval(node.Init, enclosedEnv)
-		for isTruthy(e.Eval(node.Condition, enclosedEnv)) {
-			result := e.Eval(node.Body, enclosedEnv)
-			if result.Type() == object.RETURN_VALUE_OBJ {
-				return result
-			}
-			e.Eval(node.Increment, enclosedEnv)
-		}
-		return nil
+		return e.evalForStatement(node, env)
 
 	case *ast.FunctionLiteral:
 		function := &object.Function{
+			Name:       node.Name.Value,
+			Line:       node.Token.Line,
 			Parameters: node.Parameters,
 			Body:       node.Body,
 			Env:        env,
@@ -184,6 +285,100 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment) object.Object {
 			return value
 		}
 		return &object.ReturnValue{Value: value}
+	case *ast.ThrowStatement:
+		value := e.Eval(node.Value, env)
+		if isError(value) {
+			return value
+		}
+		if caught, ok := value.(*object.ErrorValue); ok {
+			return caught.Error
+		}
+		return &object.Error{Message: value.Inspect(), Value: value}
+	case *ast.TryStatement:
+		return e.evalTryStatement(node, env)
+	case *ast.ImportStatement:
+		return e.evalImportStatement(node, env)
+	case *ast.TestStatement:
+		// Test blocks are run by the test command, not as part of the script.
+		return nil
+	case *ast.ListLiteral:
+		elements := e.evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
+		}
+		return &object.List{Elements: elements}
+	case *ast.IndexExpression:
+		left := e.Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := e.Eval(node.Index, env)
+		if isError(index) {
+			return index
+		}
+		return evalIndexExpression(left, index)
+	case *ast.GetExpression:
+		obj := e.Eval(node.Object, env)
+		if isError(obj) {
+			return obj
+		}
+		getter, ok := obj.(object.PropertyGetter)
+		if !ok {
+			return newError("Only instances have properties.")
+		}
+		value, ok := getter.GetProperty(node.Name.Value)
+		if !ok {
+			return newError("Undefined property '%s'.", node.Name.Value)
+		}
+		return value
+	}
+	return nil
+}
+
+// stampError records where an error was raised. Errors are stamped once, by
+// the innermost statement they escape from, so rethrowing keeps the origin.
+func (e *Evaluator) stampError(err *object.Error) {
+	if err.Line != 0 {
+		return
+	}
+	err.Line = e.line
+	err.Stack = e.stackTrace()
+}
+
+// stackTrace renders the active calls innermost first, in the same shape as
+// the reference clox implementation.
+func (e *Evaluator) stackTrace() string {
+	var out bytes.Buffer
+	line := e.line
+	for i := len(e.frames) - 1; i >= 0; i-- {
+		fmt.Fprintf(&out, "[line %d] in %s()\n", line, e.frames[i].name)
+		line = e.frames[i].callLine
+	}
+	fmt.Fprintf(&out, "[line %d] in script", line)
+	return out.String()
+}
+
+func (e *Evaluator) evalTryStatement(node *ast.TryStatement, env *object.Environment) object.Object {
+	result := e.Eval(node.Block, env)
+
+	if err, ok := result.(*object.Error); ok && node.Catch != nil {
+		e.stampError(err)
+		catchEnv := object.NewEnclosedEnvironment(env)
+		if node.CatchParam != nil {
+			catchEnv.Define(node.CatchParam.Value, &object.ErrorValue{Error: err})
+		}
+		result = e.Eval(node.Catch, catchEnv)
+	}
+
+	if node.Finally != nil {
+		finallyResult := e.Eval(node.Finally, env)
+		if isReturnOrError(finallyResult) {
+			return finallyResult
+		}
+	}
+
+	if isReturnOrError(result) {
+		return result
 	}
 	return nil
 }
@@ -206,13 +401,63 @@ func newError(format string, a ...interface{}) *object.Error {
 	return &object.Error{Message: fmt.Sprintf(format, a...)}
 }
 
+// isError reports whether obj is unwinding the stack: either a runtime
+// error or a request to exit the script.
 func isError(obj object.Object) bool {
 	if obj != nil {
-		return obj.Type() == object.ERROR_OBJ
+		return obj.Type() == object.ERROR_OBJ || obj.Type() == object.EXIT_OBJ
+	}
+	return false
+}
+
+func isReturnOrError(obj object.Object) bool {
+	if obj != nil {
+		return obj.Type() == object.RETURN_VALUE_OBJ || isError(obj)
 	}
 	return false
 }
 
+// evalForStatement runs a for loop with per-iteration bindings: every
+// iteration gets a fresh copy of the variables declared in the initializer,
+// and the increment runs against the copy for the next iteration. Closures
+// created in the body therefore capture the value of that iteration only.
+func (e *Evaluator) evalForStatement(node *ast.ForStatement, env *object.Environment) object.Object {
+	iterEnv := object.NewEnclosedEnvironment(env)
+	if init := e.Eval(node.Init, iterEnv); isError(init) {
+		return init
+	}
+
+	for {
+		if node.Condition != nil {
+			condition := e.Eval(node.Condition, iterEnv)
+			if isError(condition) {
+				return condition
+			}
+			e.coverBranch(node, isTruthy(condition))
+			if !isTruthy(condition) {
+				return nil
+			}
+		}
+
+		result := e.Eval(node.Body, iterEnv)
+		if isReturnOrError(result) {
+			return result
+		}
+
+		iterEnv = iterEnv.Clone()
+		if increment := e.Eval(node.Increment, iterEnv); isError(increment) {
+			return increment
+		}
+	}
+}
+
+// Exec runs the statements of a program in env. Unlike Eval it neither
+// echoes the value of a trailing expression nor prints uncaught errors,
+// leaving both to the caller.
+func (e *Evaluator) Exec(program *ast.Program, env *object.Environment) object.Object {
+	return unwrapReturnValue(e.evalBlockStatement(program.Statements, env))
+}
+
 func (e *Evaluator) evalProgram(stmts []ast.Statement, env *object.Environment) object.Object {
 	result := e.evalBlockStatement(stmts, env)
 
@@ -224,11 +469,12 @@ func (e *Evaluator) evalProgram(stmts []ast.Statement, env *object.Environment)
 		return result.(*object.ReturnValue).Value
 	}
 
-	if result.Type() == object.ERROR_OBJ {
+	if err, ok := result.(*object.Error); ok {
+		io.WriteString(e.stderr, err.Message)
 		return result
 	}
 
-	if result.Type() == object.PRINT_OBJ {
+	if result.Type() == object.PRINT_OBJ || result.Type() == object.EXIT_OBJ {
 		return result
 	}
 
@@ -257,7 +503,9 @@ func (e *Evaluator) evalBlockStatement(stmts []ast.Statement, env *object.Enviro
 		case *object.ReturnValue:
 			return result
 		case *object.Error:
-			io.WriteString(e.stderr, result.Message)
+			e.stampError(result)
+			return result
+		case *object.Exit:
 			return result
 		case *object.Print:
 			continue
@@ -267,23 +515,51 @@ func (e *Evaluator) evalBlockStatement(stmts []ast.Statement, env *object.Enviro
 	return result
 }
 
-func (e *Evaluator) evalAndExpression(left, right ast.Node, env *object.Environment) object.Object {
-	leftResult := e.Eval(left, env)
+func (e *Evaluator) evalAndExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	leftResult := e.Eval(node.Left, env)
+	if isError(leftResult) {
+		return leftResult
+	}
+	e.coverBranch(node, isTruthy(leftResult))
 	if !isTruthy(leftResult) {
 		return FALSE
 	}
-	return e.Eval(right, env)
+	return e.Eval(node.Right, env)
 }
 
-func (e *Evaluator) evalOrExpression(left, right ast.Node, env *object.Environment) object.Object {
-	leftResult := e.Eval(left, env)
+func (e *Evaluator) evalOrExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	leftResult := e.Eval(node.Left, env)
+	if isError(leftResult) {
+		return leftResult
+	}
+	e.coverBranch(node, isTruthy(leftResult))
 	if isTruthy(leftResult) {
 		return leftResult
 	}
-	rightResult := e.Eval(right, env)
+	rightResult := e.Eval(node.Right, env)
 	return rightResult
 }
 
+// coverBranch records the outcome of a branch condition when coverage is on.
+func (e *Evaluator) coverBranch(node ast.Node, taken bool) {
+	if e.Coverage != nil {
+		e.Coverage.Branch(node, taken)
+	}
+}
+
+// evalCoalesceExpression returns left unless it is nil, evaluating right
+// only in that case.
+func (e *Evaluator) evalCoalesceExpression(left, right ast.Node, env *object.Environment) object.Object {
+	leftResult := e.Eval(left, env)
+	if isError(leftResult) {
+		return leftResult
+	}
+	if leftResult != nil && leftResult != NIL {
+		return leftResult
+	}
+	return e.Eval(right, env)
+}
+
 func evalPrefixExpression(operator string, right object.Object) object.Object {
 	switch operator {
 	case "!":
@@ -293,6 +569,15 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 			return newError("Operand must be a number.")
 		}
 		return evalMinusOperatorExpression(right)
+	case "~":
+		if right.Type() != object.NUMBER_OBJ {
+			return newError("Operand must be a number.")
+		}
+		value, ok := toInt(right)
+		if !ok {
+			return newError("Operand must be an integer.")
+		}
+		return &object.Number{Value: float64(^value)}
 	}
 	return nil
 }
@@ -326,19 +611,52 @@ func evalInfixExpression(operator string, left, right object.Object) object.Obje
 		return evalStringInfixExpression(operator, left, right)
 	}
 
-	if operator == "==" || operator == "!=" {
-		if left.Type() != right.Type() {
-			return FALSE
-		}
+	if operator == "==" {
+		return nativeToBoolean(objectsEqual(left, right))
+	}
+	if operator == "!=" {
+		return nativeToBoolean(!objectsEqual(left, right))
+	}
 
-		if operator == "==" {
-			return nativeToBoolean(left.Inspect() == right.Inspect())
-		}
+	return newError("Operands must be numbers.")
+}
 
-		return nativeToBoolean(left.Inspect() != right.Inspect())
+// objectsEqual reports whether two values are equal under ==. Lists are
+// compared element by element and maps key by key, in any order; other
+// values of the same type by their printed form.
+func objectsEqual(left, right object.Object) bool {
+	if left.Type() != right.Type() {
+		return false
 	}
 
-	return newError("Operands must be numbers.")
+	switch left := left.(type) {
+	case *object.Number:
+		return left.Value == right.(*object.Number).Value
+	case *object.List:
+		other := right.(*object.List)
+		if len(left.Elements) != len(other.Elements) {
+			return false
+		}
+		for i, el := range left.Elements {
+			if !objectsEqual(el, other.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *object.Map:
+		other := right.(*object.Map)
+		if len(left.Keys) != len(other.Keys) {
+			return false
+		}
+		for _, key := range left.Keys {
+			value, ok := other.Get(key)
+			if !ok || !objectsEqual(left.Pairs[key], value) {
+				return false
+			}
+		}
+		return true
+	}
+	return left.Inspect() == right.Inspect()
 }
 
 func evalNumberInfixExpression(operator string, left, right object.Object) object.Object {
@@ -354,6 +672,19 @@ func evalNumberInfixExpression(operator string, left, right object.Object) objec
 		return &object.Number{Value: leftValue * rightValue}
 	case "/":
 		return &object.Number{Value: leftValue / rightValue}
+	case "%", "div":
+		quotient, remainder, err := floorDivMod(leftValue, rightValue)
+		if err != nil {
+			return err
+		}
+		if operator == "div" {
+			return &object.Number{Value: quotient}
+		}
+		return &object.Number{Value: remainder}
+	case "**":
+		return &object.Number{Value: math.Pow(leftValue, rightValue)}
+	case "&", "|", "^", "<<", ">>":
+		return evalBitwiseExpression(operator, left, right)
 	case ">":
 		return nativeToBoolean(leftValue > rightValue)
 	case ">=":
@@ -370,6 +701,51 @@ func evalNumberInfixExpression(operator string, left, right object.Object) objec
 	return nil
 }
 
+// evalBitwiseExpression applies a bitwise operator to two numbers that must
+// hold integral values.
+func evalBitwiseExpression(operator string, left, right object.Object) object.Object {
+	leftValue, ok := toInt(left)
+	if !ok {
+		return newError("Operands must be integers.")
+	}
+	rightValue, ok := toInt(right)
+	if !ok {
+		return newError("Operands must be integers.")
+	}
+
+	switch operator {
+	case "&":
+		return &object.Number{Value: float64(leftValue & rightValue)}
+	case "|":
+		return &object.Number{Value: float64(leftValue | rightValue)}
+	case "^":
+		return &object.Number{Value: float64(leftValue ^ rightValue)}
+	}
+
+	if rightValue < 0 {
+		return newError("Shift count must be non-negative.")
+	}
+	if operator == "<<" {
+		return &object.Number{Value: float64(leftValue << rightValue)}
+	}
+	return &object.Number{Value: float64(leftValue >> rightValue)}
+}
+
+func (e *Evaluator) evalInterpolatedString(node *ast.InterpolatedString, env *object.Environment) object.Object {
+	var out strings.Builder
+	for _, part := range node.Parts {
+		val := e.Eval(part, env)
+		if isError(val) {
+			return val
+		}
+		if val == nil {
+			val = NIL
+		}
+		out.WriteString(val.Inspect())
+	}
+	return &object.String{Value: out.String()}
+}
+
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
 	leftValue := left.(*object.String).Value
 	rightValue := right.(*object.String).Value
@@ -381,10 +757,149 @@ func evalStringInfixExpression(operator string, left, right object.Object) objec
 		return nativeToBoolean(leftValue == rightValue)
 	case "!=":
 		return nativeToBoolean(leftValue != rightValue)
-	case "*":
-		return newError("Operands must be numbers.")
 	}
-	return nil
+	return newError("Operands must be numbers.")
+}
+
+func evalIndexExpression(left, index object.Object) object.Object {
+	if m, ok := left.(*object.Map); ok {
+		key, ok := index.(*object.String)
+		if !ok {
+			return newError("Map keys must be strings.")
+		}
+		if value, ok := m.Get(key.Value); ok {
+			return value
+		}
+		return NIL
+	}
+
+	i, ok := toInt(index)
+	if !ok {
+		return newError("Index must be an integer.")
+	}
+
+	switch left := left.(type) {
+	case *object.List:
+		if i < 0 || i >= len(left.Elements) {
+			return newError("Index out of range.")
+		}
+		return left.Elements[i]
+	case *object.String:
+		runes := []rune(left.Value)
+		if i < 0 || i >= len(runes) {
+			return newError("Index out of range.")
+		}
+		return &object.String{Value: string(runes[i])}
+	}
+	return newError("Only lists, strings and maps can be indexed.")
+}
+
+// reference is an assignable location whose container and index have been
+// evaluated already, so compound assignments evaluate their target once.
+type reference struct {
+	load  func() object.Object
+	store func(object.Object) object.Object
+	err   object.Object
+}
+
+func (e *Evaluator) evalReference(target ast.Expression, env *object.Environment) reference {
+	switch target := target.(type) {
+	case *ast.Identifier:
+		return reference{
+			load: func() object.Object { return e.evalIdentifier(target, env) },
+			store: func(value object.Object) object.Object {
+				result := env.Assign(target.Value, value)
+				e.traceAssign(target.Value, value, result)
+				return result
+			},
+		}
+	case *ast.IndexExpression:
+		left := e.Eval(target.Left, env)
+		if isError(left) {
+			return reference{err: left}
+		}
+		index := e.Eval(target.Index, env)
+		if isError(index) {
+			return reference{err: index}
+		}
+		return reference{
+			load: func() object.Object { return evalIndexExpression(left, index) },
+			store: func(value object.Object) object.Object {
+				result := evalIndexAssignment(left, index, value)
+				e.traceAssign(target.String(), value, result)
+				return result
+			},
+		}
+	}
+	return reference{err: newError("Invalid assignment target.")}
+}
+
+func evalIndexAssignment(left, index, value object.Object) object.Object {
+	switch left := left.(type) {
+	case *object.Map:
+		key, ok := index.(*object.String)
+		if !ok {
+			return newError("Map keys must be strings.")
+		}
+		left.Set(key.Value, value)
+		return value
+	case *object.List:
+		i, ok := toInt(index)
+		if !ok {
+			return newError("Index must be an integer.")
+		}
+		if i < 0 || i >= len(left.Elements) {
+			return newError("Index out of range.")
+		}
+		left.Elements[i] = value
+		return value
+	}
+	return newError("Only lists and maps support index assignment.")
+}
+
+func (e *Evaluator) evalCompoundAssignExpression(node *ast.CompoundAssignExpression, env *object.Environment) object.Object {
+	ref := e.evalReference(node.Target, env)
+	if ref.err != nil {
+		return ref.err
+	}
+	current := ref.load()
+	if isError(current) {
+		return current
+	}
+	value := e.Eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+	result := evalInfixExpression(node.Operator, current, value)
+	if isError(result) {
+		return result
+	}
+	return ref.store(result)
+}
+
+func (e *Evaluator) evalUpdateExpression(node *ast.UpdateExpression, env *object.Environment) object.Object {
+	ref := e.evalReference(node.Target, env)
+	if ref.err != nil {
+		return ref.err
+	}
+	current := ref.load()
+	if isError(current) {
+		return current
+	}
+	number, ok := current.(*object.Number)
+	if !ok {
+		return newError("Operand must be a number.")
+	}
+
+	delta := 1.0
+	if node.Operator == "--" {
+		delta = -1
+	}
+	updated := ref.store(&object.Number{Value: number.Value + delta})
+	if isError(updated) || node.Prefix {
+		return updated
+	}
+	return number
 }
 
 func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
@@ -399,9 +914,26 @@ func (e *Evaluator) applyFunction(fn object.Object, args []object.Object) object
 
 	switch fn := fn.(type) {
 	case *object.Function:
+		callLine := e.line
+		e.frames = append(e.frames, frame{name: fn.Name, callLine: callLine})
+		defer func() {
+			e.frames = e.frames[:len(e.frames)-1]
+			e.line = callLine
+		}()
+		if e.Profiler != nil {
+			e.Profiler.Enter(fn.Name, fn.Line)
+			defer e.Profiler.Leave()
+		}
+
+		if e.Tracer != nil {
+			e.traceCall(fn, args)
+		}
 		extendEnv := extendFunctionEnv(fn, args)
-		returnedValue := e.Eval(fn.Body, extendEnv)
-		return unwrapReturnValue(returnedValue)
+		result := functionResult(e.Eval(fn.Body, extendEnv))
+		if e.Tracer != nil {
+			e.traceReturn(fn, result)
+		}
+		return result
 
 	case *object.NativeFunction:
 		return fn.Fn(args...)
@@ -411,6 +943,34 @@ func (e *Evaluator) applyFunction(fn object.Object, args []object.Object) object
 	}
 }
 
+// Call invokes fn with args the way a call expression does. It lets the
+// host and natives call back into Lox functions.
+func (e *Evaluator) Call(fn object.Object, args ...object.Object) object.Object {
+	switch f := fn.(type) {
+	case *object.Function:
+		if len(args) != len(f.Parameters) {
+			return newError("Expected %d arguments but got %d.", len(f.Parameters), len(args))
+		}
+		return e.applyFunction(fn, args)
+	case *object.NativeFunction:
+		return e.applyFunction(fn, args)
+	}
+	return newError("Can only call functions and classes.")
+}
+
+// functionResult is the value of a call to a Lox function whose body
+// evaluated to obj. A body that ends without a return statement yields nil,
+// not the value of its last statement.
+func functionResult(obj object.Object) object.Object {
+	if isError(obj) {
+		return obj
+	}
+	if returnValue, ok := obj.(*object.ReturnValue); ok {
+		return returnValue.Value
+	}
+	return NIL
+}
+
 func unwrapReturnValue(obj object.Object) object.Object {
 	if returnValue, ok := obj.(*object.ReturnValue); ok {
 		return returnValue.Value
@@ -423,6 +983,10 @@ func (e *Evaluator) evalIdentifier(node *ast.Identifier, env *object.Environment
 		return val
 	}
 
+	if native, ok := e.natives[node.Value]; ok {
+		return native
+	}
+
 	if builtin, ok := builtins[node.Value]; ok {
 		return builtin
 	}
@@ -438,6 +1002,9 @@ func (e *Evaluator) evalExpressions(exps []ast.Expression, env *object.Environme
 		if isError(evaluated) {
 			return []object.Object{evaluated}
 		}
+		if evaluated == nil { // a call to a function that returned nothing
+			evaluated = NIL
+		}
 		result = append(result, evaluated)
 	}
 	return result
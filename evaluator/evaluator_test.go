@@ -3,13 +3,19 @@ package evaluator
 import (
 	"bytes"
 	"io"
+	"math"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/codecrafters-io/interpreter-starter-go/coverage"
 	"github.com/codecrafters-io/interpreter-starter-go/lexer"
 	"github.com/codecrafters-io/interpreter-starter-go/object"
 	"github.com/codecrafters-io/interpreter-starter-go/parser"
+	"github.com/codecrafters-io/interpreter-starter-go/profile"
 )
 
 func checkParserErrors(t *testing.T, p *parser.Parser) {
@@ -27,8 +33,8 @@ func checkParserErrors(t *testing.T, p *parser.Parser) {
 func testEval(t *testing.T, input string, stdout, stderr io.Writer) object.Object {
 	l := lexer.New(input)
 	p := parser.New(l)
-	checkParserErrors(t, p)
 	program := p.ParseProgram()
+	checkParserErrors(t, p)
 	env := object.NewEnvironment()
 	e := NewEvaluator(&stdout, &stderr)
 	return e.Eval(program, env)
@@ -285,6 +291,23 @@ func TestError(t *testing.T) {
 		{`"foo" * "bar"`, "Operands must be numbers."},
 		{`("foo" * "bar")`, "Operands must be numbers."},
 		{`false / true`, "Operands must be numbers."},
+		{`"a" % 2`, "Operands must be numbers."},
+		{`"a" % "b"`, "Operands must be numbers."},
+		{`"a" ** "b"`, "Operands must be numbers."},
+		{`"a" div "b"`, "Operands must be numbers."},
+		{`"a" & "b"`, "Operands must be numbers."},
+		{`"a" | "b"`, "Operands must be numbers."},
+		{`"a" ^ "b"`, "Operands must be numbers."},
+		{`"a" << "b"`, "Operands must be numbers."},
+		{`"a" >> "b"`, "Operands must be numbers."},
+		{`"a" - "b"`, "Operands must be numbers."},
+		{`"a" < "b"`, "Operands must be numbers."},
+		{`~"a"`, "Operand must be a number."},
+		{`~1.5`, "Operand must be an integer."},
+		{`1.5 & 1`, "Operands must be integers."},
+		{`1 << -1`, "Shift count must be non-negative."},
+		{`1 % 0`, "Division by zero."},
+		{`1 div 0`, "Division by zero."},
 	}
 
 	for _, tt := range tests {
@@ -694,6 +717,25 @@ func TestEmptyFunction(t *testing.T) {
 	testStdout(t, stdout, "nil\n")
 }
 
+func TestFunctionWithoutReturnIsNil(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fun f() {} print "${f()}";`, "nil\n"},
+		{`fun f() { print "x"; } print f();`, "x\nnil\n"},
+		{`fun f() { var a = 1; } print f() == nil;`, "true\n"},
+		{`fun f() { 1 + 1; } print f();`, "nil\n"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		testEval(t, tt.input, &stdout, &stderr)
+		testStdout(t, stdout, tt.expected)
+		testStderr(t, stderr, "")
+	}
+}
+
 func TestFunctionReturnNilWithSemicolon(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	testEval(t, `fun f() {
@@ -765,3 +807,1371 @@ func TestDoNotPrintReturnValue(t *testing.T) {
 	testEval(t, `fun f() { return 1; } f();`, &stdout, &stderr)
 	testStdout(t, stdout, "")
 }
+
+func TestForStatementEmptyBody(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `for (var i = 0; i < 3; i = i + 1) {} print "done";`, &stdout, &stderr)
+	testStdout(t, stdout, "done\n")
+}
+
+func TestForStatementPerIterationBinding(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var first;
+		var second;
+		var third;
+		for (var i = 0; i < 3; i = i + 1) {
+			fun show() { print i; }
+			if (i == 0) first = show;
+			if (i == 1) second = show;
+			if (i == 2) third = show;
+		}
+		first();
+		second();
+		third();`, &stdout, &stderr)
+	testStdout(t, stdout, "0\n1\n2\n")
+}
+
+func TestForStatementBodyUpdatesCarryOver(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `for (var i = 0; i < 5; i = i + 1) { i = i + 1; print i; }`, &stdout, &stderr)
+	testStdout(t, stdout, "1\n3\n5\n")
+}
+
+func TestClosureCounter(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		fun makeCounter() {
+			var count = 0;
+			fun increment() {
+				count = count + 1;
+				return count;
+			}
+			return increment;
+		}
+
+		var a = makeCounter();
+		var b = makeCounter();
+		print a();
+		print a();
+		print b();
+		print a();`, &stdout, &stderr)
+	testStdout(t, stdout, "1\n2\n1\n3\n")
+}
+
+func TestClosureSharesLoopVariableWithinIteration(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var get;
+		var bump;
+		for (var i = 0; i < 1; i = i + 1) {
+			fun g() { return i; }
+			fun b() { i = i + 10; }
+			get = g;
+			bump = b;
+		}
+		bump();
+		print get();`, &stdout, &stderr)
+	testStdout(t, stdout, "10\n")
+}
+
+func TestWhileStatementConditionError(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	evaluated := testEval(t, `while (-"a") print "never";`, &stdout, &stderr)
+	testErrorObject(t, evaluated, "Operand must be a number.")
+	testStdout(t, stdout, "")
+}
+
+func TestNestedBlockErrorReportedOnce(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `fun f() { { -true; } } f();`, &stdout, &stderr)
+	testStderr(t, stderr, "Operand must be a number.")
+}
+
+func TestTryCatchThrow(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { throw "boom"; } catch (e) { print e.message; }`, "boom\n"},
+		{`try { print "ok"; } catch (e) { print "never"; }`, "ok\n"},
+		{`try { -"a"; } catch (e) { print e.message; }`, "Operand must be a number.\n"},
+		{`try { missing; } catch (e) { print e.message; }`, "undefined variable: missing\n"},
+		{`fun f(a) {} try { f(); } catch (e) { print e.message; }`, "Expected 1 arguments but got 0.\n"},
+		{`try { throw 42; } catch (e) { print e.value + 1; }`, "43\n"},
+		{`try { throw "x"; } catch { print "caught"; }`, "caught\n"},
+		{`try { throw "x"; } catch (e) { print "catch"; } finally { print "finally"; }`, "catch\nfinally\n"},
+		{`try { print "try"; } finally { print "finally"; }`, "try\nfinally\n"},
+		{`
+			try {
+				try { throw "inner"; } finally { print "cleanup"; }
+			} catch (e) {
+				print e.message;
+			}`, "cleanup\ninner\n"},
+		{`
+			try {
+				try { throw "first"; } catch (e) { throw e; }
+			} catch (e) {
+				print e.message;
+			}`, "first\n"},
+		{`
+			fun f() {
+				try { return "try"; } finally { print "finally"; }
+			}
+			print f();`, "finally\ntry\n"},
+		{`
+			fun f() {
+				try { throw "x"; } catch (e) { return "recovered"; }
+				return "after";
+			}
+			print f();`, "recovered\n"},
+		{`
+			var i = 0;
+			while (i < 3) {
+				try { if (i == 1) throw "skip"; print i; } catch (e) { print e.message; }
+				i = i + 1;
+			}`, "0\nskip\n2\n"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		testEval(t, tt.input, &stdout, &stderr)
+		testStdout(t, stdout, tt.expected)
+		testStderr(t, stderr, "")
+	}
+}
+
+func TestErrorLineAndStack(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `fun inner() {
+  throw "bad";
+}
+fun outer() {
+  inner();
+}
+try {
+  outer();
+} catch (e) {
+  print e.line;
+  print e.stack;
+}`, &stdout, &stderr)
+	testStdout(t, stdout, "2\n[line 2] in inner()\n[line 5] in outer()\n[line 8] in script\n")
+}
+
+func TestUncaughtThrow(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	evaluated := testEval(t, `print "before"; throw "boom"; print "after";`, &stdout, &stderr)
+	testErrorObject(t, evaluated, "boom")
+	testStdout(t, stdout, "before\n")
+	testStderr(t, stderr, "boom")
+}
+
+func TestFinallyRunsWhenErrorEscapes(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	evaluated := testEval(t, `try { throw "boom"; } finally { print "finally"; }`, &stdout, &stderr)
+	testErrorObject(t, evaluated, "boom")
+	testStdout(t, stdout, "finally\n")
+}
+
+func TestUndefinedProperty(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { throw "x"; } catch (e) { e.nope; }`, "Undefined property 'nope'."},
+		{`var a = 1; a.b;`, "Only instances have properties."},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func writeModuleFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	return dir
+}
+
+func testEvalFile(t *testing.T, path string, stdout, stderr io.Writer) object.Object {
+	input, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	l := lexer.New(string(input))
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	e := NewEvaluator(&stdout, &stderr)
+	e.ScriptPath = path
+	return e.Eval(program, object.NewEnvironment())
+}
+
+func TestImportModule(t *testing.T) {
+	dir := writeModuleFiles(t, map[string]string{
+		"main.lox": `
+			import "lib/math.lox" as m;
+			import { double } from "lib/math.lox";
+			print m.square(4);
+			print double(4);
+			print m.name;
+			print m;`,
+		"lib/math.lox": `
+			print "loading math";
+			var name = "math";
+			fun square(x) { return x * x; }
+			fun double(x) { return _twice(x); }
+			fun _twice(x) { return x + x; }`,
+	})
+
+	var stdout, stderr bytes.Buffer
+	testEvalFile(t, filepath.Join(dir, "main.lox"), &stdout, &stderr)
+	testStdout(t, stdout, "loading math\n16\n8\nmath\n<module math>\n")
+	testStderr(t, stderr, "")
+}
+
+func TestImportRelativeToImportingFile(t *testing.T) {
+	dir := writeModuleFiles(t, map[string]string{
+		"main.lox":      `import "lib/a.lox" as a; print a.value;`,
+		"lib/a.lox":     `import "b.lox" as b; var value = b.value + 1;`,
+		"lib/b.lox":     `var value = 41;`,
+		"unrelated.lox": `var value = 0;`,
+	})
+
+	var stdout, stderr bytes.Buffer
+	testEvalFile(t, filepath.Join(dir, "main.lox"), &stdout, &stderr)
+	testStdout(t, stdout, "42\n")
+}
+
+func TestImportInsideModuleFunction(t *testing.T) {
+	dir := writeModuleFiles(t, map[string]string{
+		"main.lox":         `import "lib/a.lox" as a; print a.load();`,
+		"lib/a.lox":        `fun load() { import "nested/b.lox" as b; return b.value; }`,
+		"lib/nested/b.lox": `var value = "nested";`,
+		"nested/b.lox":     `var value = "top level";`,
+	})
+
+	var stdout, stderr bytes.Buffer
+	testEvalFile(t, filepath.Join(dir, "main.lox"), &stdout, &stderr)
+	testStdout(t, stdout, "nested\n")
+	testStderr(t, stderr, "")
+}
+
+func TestImportSearchPath(t *testing.T) {
+	libDir := writeModuleFiles(t, map[string]string{"greet.lox": `fun hello() { return "hi"; }`})
+	dir := writeModuleFiles(t, map[string]string{"main.lox": `import { hello } from "greet.lox"; print hello();`})
+
+	input, _ := os.ReadFile(filepath.Join(dir, "main.lox"))
+	p := parser.New(lexer.New(string(input)))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	var stdout, stderr bytes.Buffer
+	var out, errOut io.Writer = &stdout, &stderr
+	e := NewEvaluator(&out, &errOut)
+	e.ScriptPath = filepath.Join(dir, "main.lox")
+	e.ModulePaths = []string{libDir}
+	e.Eval(program, object.NewEnvironment())
+	testStdout(t, stdout, "hi\n")
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		files    map[string]string
+		expected string
+	}{
+		{map[string]string{"main.lox": `import "missing.lox" as m;`}, "Could not find module 'missing.lox'."},
+		{map[string]string{
+			"main.lox": `import "a.lox" as a;`,
+			"a.lox":    `import "b.lox" as b;`,
+			"b.lox":    `import "a.lox" as a;`,
+		}, "Import cycle detected: a.lox -> b.lox -> a.lox."},
+		{map[string]string{
+			"main.lox": `import { _hidden } from "a.lox";`,
+			"a.lox":    `var _hidden = 1;`,
+		}, "Module 'a' has no exported name '_hidden'."},
+		{map[string]string{
+			"main.lox": `import "a.lox" as a; a._hidden;`,
+			"a.lox":    `var _hidden = 1;`,
+		}, "Undefined property '_hidden'."},
+		{map[string]string{
+			"main.lox": `import "a.lox" as a;`,
+			"a.lox":    `throw "broken module";`,
+		}, "broken module"},
+	}
+
+	for _, tt := range tests {
+		dir := writeModuleFiles(t, tt.files)
+		var stdout, stderr bytes.Buffer
+		evaluated := testEvalFile(t, filepath.Join(dir, "main.lox"), &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestImportEvaluatesModuleOnce(t *testing.T) {
+	dir := writeModuleFiles(t, map[string]string{
+		"main.lox":   `import "a.lox" as a; import "b.lox" as b; print a.count() + b.count();`,
+		"a.lox":      `import "shared.lox" as s; fun count() { return s.bump(); }`,
+		"b.lox":      `import "shared.lox" as s; fun count() { return s.bump(); }`,
+		"shared.lox": `print "shared loaded"; var n = 0; fun bump() { n = n + 1; return n; }`,
+	})
+
+	var stdout, stderr bytes.Buffer
+	testEvalFile(t, filepath.Join(dir, "main.lox"), &stdout, &stderr)
+	testStdout(t, stdout, "shared loaded\n3\n")
+}
+
+func TestListLiteralAndIndex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`print [];`, "[]\n"},
+		{`print [1, "two", true, nil];`, "[1, two, true, nil]\n"},
+		{`var xs = [1, 2 + 3, [4]]; print xs[1]; print xs[2][0];`, "5\n4\n"},
+		{`print "héllo"[1];`, "é\n"},
+		{`fun f() {} print [f(), 1];`, "[nil, 1]\n"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		testEval(t, tt.input, &stdout, &stderr)
+		testStdout(t, stdout, tt.expected)
+		testStderr(t, stderr, "")
+	}
+}
+
+func TestListEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`print [1, [2, "x"]] == [1, [2, "x"]];`, "true\n"},
+		{`print [1] == ["1"];`, "false\n"},
+		{`print ["a, b"] == ["a", "b"];`, "false\n"},
+		{`print [1, 2] != [1];`, "true\n"},
+		{`print [nil] == [nil];`, "true\n"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		testEval(t, tt.input, &stdout, &stderr)
+		testStdout(t, stdout, tt.expected)
+		testStderr(t, stderr, "")
+	}
+}
+
+func TestIndexErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[1, 2][2];`, "Index out of range."},
+		{`[1, 2][-1];`, "Index out of range."},
+		{`[1, 2][0.5];`, "Index must be an integer."},
+		{`"ab"["a"];`, "Index must be an integer."},
+		{`var a = 1; a[0];`, "Only lists, strings and maps can be indexed."},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`print len("hello");`, "5"},
+		{`print len("héllo wörld");`, "11"},
+		{`print len([1, 2, 3]);`, "3"},
+		{`print substr("héllo", 1, 3);`, "él"},
+		{`print substr("héllo", 2);`, "llo"},
+		{`print indexOf("héllo", "l");`, "2"},
+		{`print indexOf("hello", "z");`, "-1"},
+		{`print split("a,b,,c", ",");`, "[a, b, , c]"},
+		{`print len(split("a,b,c", ","));`, "3"},
+		{`print join(["a", 1, true], "-");`, "a-1-true"},
+		{`print upper("héllo");`, "HÉLLO"},
+		{`print lower("ÀB");`, "àb"},
+		{`print trim("   hi  ");`, "hi"},
+		{`print replace("a-b-c", "-", "+");`, "a+b+c"},
+		{`print startsWith("hello", "he");`, "true"},
+		{`print endsWith("hello", "he");`, "false"},
+		{`print repeat("ab", 3);`, "ababab"},
+		{`print chars("añb");`, "[a, ñ, b]"},
+		{`print format("{} has {} items", "cart", 3);`, "cart has 3 items"},
+		{`print format("no placeholders");`, "no placeholders"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		testEval(t, tt.input, &stdout, &stderr)
+		testStdout(t, stdout, tt.expected+"\n")
+		testStderr(t, stderr, "")
+	}
+}
+
+func TestStringBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`len(1);`, "len() expects a string, list or map."},
+		{`len();`, "Expected 1 arguments but got 0."},
+		{`upper(1);`, "upper() expects argument 1 to be a string."},
+		{`fun f() {} upper(f());`, "upper() expects argument 1 to be a string."},
+		{`split("a");`, "Expected 2 arguments but got 1."},
+		{`join("a", ",");`, "join() expects argument 1 to be a list."},
+		{`substr("abc", 2, 1);`, "substr() range out of bounds."},
+		{`substr("abc", 0, 10);`, "substr() range out of bounds."},
+		{`substr("abc", 0.5);`, "substr() start must be an integer."},
+		{`repeat("a", -1);`, "repeat() count must be a non-negative integer."},
+		{`format("{} {}", 1);`, "format() has 2 placeholders but got 1 values."},
+		{`format(1);`, "format() expects argument 1 to be a string."},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{`floor(2.7)`, 2},
+		{`floor(-2.5)`, -3},
+		{`ceil(2.1)`, 3},
+		{`round(2.5)`, 3},
+		{`round(-2.5)`, -3},
+		{`abs(-4)`, 4},
+		{`sqrt(16)`, 4},
+		{`pow(2, 10)`, 1024},
+		{`min(3, 1, 2)`, 1},
+		{`max(3, 1, 2)`, 3},
+		{`sin(0)`, 0},
+		{`cos(0)`, 1},
+		{`tan(0)`, 0},
+		{`log(1)`, 0},
+		{`exp(0)`, 1},
+		{`pi`, math.Pi},
+		{`inf`, math.Inf(1)},
+		{`div(7, 2)`, 3},
+		{`div(-7, 2)`, -4},
+		{`mod(7, 3)`, 1},
+		{`mod(-7, 3)`, 2},
+		{`mod(7, -3)`, -2},
+		{`mod(5.5, 2)`, 1.5},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testNumberObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestMathNaN(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`isNaN(nan)`, true},
+		{`isNaN(sqrt(-1))`, true},
+		{`isNaN(1)`, false},
+		{`nan == nan`, false},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestMathBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`sqrt("4");`, "sqrt() expects argument 1 to be a number."},
+		{`pow(2);`, "Expected 2 arguments but got 1."},
+		{`max();`, "Expected at least 1 arguments but got 0."},
+		{`min(1, "2");`, "min() expects argument 2 to be a number."},
+		{`div(1, 0);`, "Division by zero."},
+		{`mod(1, 0);`, "Division by zero."},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func testEvalSeeded(t *testing.T, input string, seed int64) string {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	var stdout, stderr bytes.Buffer
+	var out, errOut io.Writer = &stdout, &stderr
+	e := NewEvaluator(&out, &errOut)
+	e.Seed(seed)
+	e.Eval(program, object.NewEnvironment())
+	testStderr(t, stderr, "")
+	return stdout.String()
+}
+
+func TestRandomBuiltinsAreReproducible(t *testing.T) {
+	input := `
+		print random();
+		print randomInt(1, 100);
+		print shuffle([1, 2, 3, 4, 5]);`
+
+	first := testEvalSeeded(t, input, 42)
+	second := testEvalSeeded(t, input, 42)
+	if first != second {
+		t.Errorf("expected identical output for the same seed, got %q and %q", first, second)
+	}
+
+	other := testEvalSeeded(t, input, 7)
+	if first == other {
+		t.Errorf("expected different output for different seeds, got %q", first)
+	}
+}
+
+func TestSeedBuiltin(t *testing.T) {
+	output := testEvalSeeded(t, `
+		seed(5);
+		var a = random();
+		seed(5);
+		print a == random();`, 1)
+	if output != "true\n" {
+		t.Errorf("expected seed() to restart the sequence, got %q", output)
+	}
+}
+
+func TestRandomBuiltinRanges(t *testing.T) {
+	output := testEvalSeeded(t, `
+		var ok = true;
+		for (var i = 0; i < 200; i = i + 1) {
+			var r = random();
+			var n = randomInt(-2, 2);
+			if (r < 0 or r >= 1) ok = false;
+			if (n < -2 or n > 2 or floor(n) != n) ok = false;
+		}
+		print ok;
+		print randomInt(3, 3);
+		print len(shuffle([1, 2, 3]));
+		var wide = randomInt(-9007199254740992, 9007199254740992);
+		print floor(wide) == wide;`, 99)
+	if output != "true\n3\n3\ntrue\n" {
+		t.Errorf("unexpected output %q", output)
+	}
+}
+
+func TestRandomBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`random(1);`, "Expected 0 arguments but got 1."},
+		{`randomInt(5, 1);`, "randomInt() lower bound must not exceed upper bound."},
+		{`randomInt(0.5, 1);`, "randomInt() bounds must be integers."},
+		{`randomInt(-100000000000000000000, 100000000000000000000);`, "randomInt() bounds must be integers."},
+		{`randomInt(0, 9007199254740994);`, "randomInt() bounds must be integers."},
+		{`shuffle("abc");`, "shuffle() expects argument 1 to be a list."},
+		{`seed(1.5);`, "seed() expects an integer."},
+		{`seed(100000000000000000000);`, "seed() expects an integer."},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestClockUsesInjectedTime(t *testing.T) {
+	input := `print clock();`
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	var stdout, stderr bytes.Buffer
+	var out, errOut io.Writer = &stdout, &stderr
+	e := NewEvaluator(&out, &errOut)
+	e.Clock = FrozenClock(time.Unix(1700000000, 250*int64(time.Millisecond)))
+	e.Eval(program, object.NewEnvironment())
+
+	testStdout(t, stdout, "1.70000000025e+09\n")
+}
+
+func TestClockHasSubSecondPrecision(t *testing.T) {
+	input := `var start = clock(); var elapsed = clock() - start; print elapsed > 0 and elapsed < 1;`
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	var stdout, stderr bytes.Buffer
+	var out, errOut io.Writer = &stdout, &stderr
+	e := NewEvaluator(&out, &errOut)
+	now := time.Unix(100, 0)
+	e.Clock = func() time.Time {
+		now = now.Add(10 * time.Millisecond)
+		return now
+	}
+	e.Eval(program, object.NewEnvironment())
+
+	testStdout(t, stdout, "true\n")
+}
+
+func TestFileBuiltins(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var path = "`+path+`";
+		print exists(path);
+		writeFile(path, "one
+");
+		appendFile(path, "two
+three
+");
+		print exists(path);
+		print readLines(path);
+		print len(readFile(path));
+		print listDir("`+dir+`");
+		removeFile(path);
+		print exists(path);`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, "false\ntrue\n[one, two, three]\n14\n[notes.txt]\nfalse\n")
+}
+
+func TestFileBuiltinErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`readFile("` + missing + `");`, "open " + missing + ": no such file or directory"},
+		{`readLines("` + missing + `");`, "open " + missing + ": no such file or directory"},
+		{`removeFile("` + missing + `");`, "remove " + missing + ": no such file or directory"},
+		{`listDir("` + missing + `");`, "open " + missing + ": no such file or directory"},
+		{`writeFile("` + missing + `", 1);`, "writeFile() expects argument 2 to be a string."},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestFileErrorsAreCatchable(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	var stdout, stderr bytes.Buffer
+	testEval(t, `try { readFile("`+missing+`"); } catch (e) { print "recovered"; }`, &stdout, &stderr)
+	testStdout(t, stdout, "recovered\n")
+}
+
+func newTestEvaluator(stdout, stderr *bytes.Buffer) *Evaluator {
+	var out, errOut io.Writer = stdout, stderr
+	return NewEvaluator(&out, &errOut)
+}
+
+func evalWith(t *testing.T, e *Evaluator, input string) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	return e.Eval(program, object.NewEnvironment())
+}
+
+func TestReadLineAndInput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	e := newTestEvaluator(&stdout, &stderr)
+	e.Stdin = strings.NewReader("alice\r\nbob\nlast")
+
+	evalWith(t, e, `
+		var name = input("name? ");
+		print "hello " + name;
+		print readLine();
+		print readLine();
+		print readLine();`)
+
+	testStdout(t, stdout, "name? hello alice\nbob\nlast\nnil\n")
+	testStderr(t, stderr, "")
+}
+
+func TestReadLineCRLFWithoutFinalNewline(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	e := newTestEvaluator(&stdout, &stderr)
+	e.Stdin = strings.NewReader("first\r\nlast\r")
+
+	evalWith(t, e, `
+		print readLine() + "|";
+		print readLine() + "|";
+		print readLine();`)
+
+	testStdout(t, stdout, "first|\nlast|\nnil\n")
+	testStderr(t, stderr, "")
+}
+
+func TestArgs(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	e := newTestEvaluator(&stdout, &stderr)
+	evalWith(t, e, `print args;`)
+	testStdout(t, stdout, "[]\n")
+
+	stdout.Reset()
+	e = newTestEvaluator(&stdout, &stderr)
+	e.SetArgs([]string{"one", "two"})
+	evalWith(t, e, `print len(args); print args[1];`)
+	testStdout(t, stdout, "2\ntwo\n")
+}
+
+func TestExit(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedCode int
+		expected     string
+	}{
+		{`print "a"; exit(); print "b";`, 0, "a\n"},
+		{`fun f() { while (true) { exit(3); } } f(); print "never";`, 3, ""},
+		{`try { exit(4); } catch (e) { print "caught"; } finally { print "finally"; }`, 4, "finally\n"},
+		{`for (var i = 0; i < 10; i = i + 1) { if (i == 2) exit(i); print i; }`, 2, "0\n1\n"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		exit, ok := evaluated.(*object.Exit)
+		if !ok {
+			t.Errorf("object is not Exit. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if exit.Code != tt.expectedCode {
+			t.Errorf("exit code wrong. got=%d, want=%d", exit.Code, tt.expectedCode)
+		}
+		testStdout(t, stdout, tt.expected)
+		testStderr(t, stderr, "")
+	}
+}
+
+func TestExitErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	evaluated := testEval(t, `exit("x");`, &stdout, &stderr)
+	testErrorObject(t, evaluated, "exit() expects an integer status code.")
+}
+
+func TestJSONParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"b": 1, "a": [true, false, null], "c": {"d": "e"}}`, "{b: 1, a: [true, false, nil], c: {d: e}}"},
+		{`[1.5, -2, "x\ny"]`, "[1.5, -2, x\ny]"},
+		{`"just a string"`, "just a string"},
+		{`  42  `, "42"},
+		{`{}`, "{}"},
+	}
+
+	for _, tt := range tests {
+		result := parseJSON(tt.input)
+		if isError(result) {
+			t.Errorf("unexpected error for %q: %s", tt.input, result.Inspect())
+			continue
+		}
+		if result.Inspect() != tt.expected {
+			t.Errorf("jsonParse(%q) wrong. got=%q, want=%q", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestMapEquality(t *testing.T) {
+	tests := []struct {
+		left, right string
+		expected    bool
+	}{
+		{`{"a": 1}`, `{"a": "1"}`, false},
+		{`{"a": 1, "b": [{}]}`, `{"b": [{}], "a": 1}`, true},
+		{`{"a": {"b": 1}}`, `{"a": {"b": 2}}`, false},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`{"a, b": null}`, `{"a": null}`, false},
+	}
+
+	for _, tt := range tests {
+		left, right := parseJSON(tt.left), parseJSON(tt.right)
+		if got := evalInfixExpression("==", left, right) == TRUE; got != tt.expected {
+			t.Errorf("%s == %s: got %t, want %t", tt.left, tt.right, got, tt.expected)
+		}
+		if got := evalInfixExpression("!=", left, right) == TRUE; got == tt.expected {
+			t.Errorf("%s != %s: got %t, want %t", tt.left, tt.right, got, !tt.expected)
+		}
+	}
+}
+
+func TestJSONParseErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[1, x]`, "jsonParse() invalid JSON at offset 5: invalid character 'x' looking for beginning of value"},
+		{`[1, 2`, "jsonParse() invalid JSON at offset 5: unexpected end of JSON input"},
+		{`1 2`, "jsonParse() invalid JSON at offset 3: unexpected data after top-level value"},
+		{``, "jsonParse() invalid JSON at offset 0: unexpected end of JSON input"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, parseJSON(tt.input), tt.expected)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(path, []byte(`{"name": "lox <3", "tags": ["a", "b"], "n": 3, "ok": true, "none": null}`), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var data = jsonParse(readFile("`+path+`"));
+		print data["name"];
+		print data["tags"][1];
+		print data["missing"];
+		print keys(data);
+		print len(data);
+		print jsonStringify(data);
+		print jsonStringify([1, [2]], 2);
+		fun void() {}
+		print jsonStringify(void());`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, `lox <3
+b
+nil
+[name, tags, n, ok, none]
+5
+{"name":"lox <3","tags":["a","b"],"n":3,"ok":true,"none":null}
+[
+  1,
+  [
+    2
+  ]
+]
+null
+`)
+}
+
+func TestJSONStringifyErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fun f() {} jsonStringify(f);`, "jsonStringify() cannot encode a function."},
+		{`jsonStringify([nan]);`, "jsonStringify() cannot encode NaN."},
+		{`jsonStringify(1, -1);`, "jsonStringify() indent must be a non-negative integer."},
+		{`jsonStringify();`, "Expected 1 or 2 arguments but got 0."},
+		{`jsonParse(1);`, "jsonParse() expects argument 1 to be a string."},
+		{`keys([]);`, "keys() expects argument 1 to be a map."},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestRegex(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var re = regex("([a-z]+)=([0-9]+)");
+		print re;
+		print re.pattern;
+		print match(re, "x=1");
+		print match("^[0-9]+$", "12a");
+		print find(re, "a=1 b=22");
+		print find(re, "nothing");
+		print findAll("[0-9]+", "a=1 b=22 c=333");
+		print captures(re, "key=42");
+		print captures("(a)|(b)", "b");
+		print replace("a=1 b=2", re, "$2=$1");
+		print replace("a.b", ".", "-");`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, `/([a-z]+)=([0-9]+)/
+([a-z]+)=([0-9]+)
+true
+false
+a=1
+nil
+[1, 22, 333]
+[key=42, key, 42]
+[b, nil, b]
+1=a 2=b
+a-b
+`)
+}
+
+func TestRegexCache(t *testing.T) {
+	first, err := compileRegex("ca+che")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Message)
+	}
+	second, _ := compileRegex("ca+che")
+	if first != second {
+		t.Errorf("pattern was compiled twice")
+	}
+}
+
+func TestRegexCacheIsBounded(t *testing.T) {
+	kept, _ := compileRegex("kept")
+	for i := 0; i < 2*maxCachedRegexes; i++ {
+		compileRegex("x" + strconv.Itoa(i))
+		compileRegex("kept") // recently used patterns stay cached
+	}
+
+	regexCache.Lock()
+	size := regexCache.order.Len()
+	regexCache.Unlock()
+	if size > maxCachedRegexes {
+		t.Errorf("cache holds %d patterns, want at most %d", size, maxCachedRegexes)
+	}
+	if again, _ := compileRegex("kept"); again != kept {
+		t.Errorf("recently used pattern was evicted")
+	}
+}
+
+func TestRegexErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`regex("(");`, "Invalid regex '(': error parsing regexp: missing closing ): `(`"},
+		{`match("(", "x");`, "Invalid regex '(': error parsing regexp: missing closing ): `(`"},
+		{`find(1, "x");`, "find() expects argument 1 to be a regex or string."},
+		{`findAll("x", 1);`, "findAll() expects argument 2 to be a string."},
+		{`captures("x");`, "Expected 2 arguments but got 1."},
+		{`replace("x", regex("x"), 1);`, "replace() expects argument 3 to be a string."},
+		{`fun f() {} replace("x", f(), "y");`, "replace() expects argument 2 to be a string."},
+		{`replace("x");`, "Expected 3 arguments but got 1."},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var name = "Ann";
+		var count = 2;
+		print "Hello ${name}, you have ${count + 1} items";
+		print "${[1, "two"]} ${nil} ${count > 1}";
+		print "outer ${"inner ${upper(name)}"}";
+		print "\${name}";`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, `Hello Ann, you have 3 items
+[1, two] nil true
+outer inner ANN
+${name}
+`)
+
+	var errOut bytes.Buffer
+	evaluated := testEval(t, `"x ${missing}";`, &stdout, &errOut)
+	testErrorObject(t, evaluated, "undefined variable: missing")
+}
+
+func TestUnicodeSource(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var café = "naïve 日本";
+		fun größe(s) { return len(s); }
+		print größe(café);
+		print café[6];
+		print café[1];
+		print substr(café, 6, 8);`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, `8
+日
+a
+日本
+`)
+}
+
+func TestArithmeticAndBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"7 % 3", 1},
+		{"-7 % 3", 2},
+		{"7.5 % 2", 1.5},
+		{"7 div 2", 3},
+		{"-7 div 2", -4},
+		{"div(7, 2) + 7 div 2", 6},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"2 ** -1", 0.5},
+		{"6 & 3", 2},
+		{"6 | 3", 7},
+		{"6 ^ 3", 5},
+		{"~5", -6},
+		{"1 << 4", 16},
+		{"-256 >> 2", -64},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testNumberObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestCompoundAssignment(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var x = 1;
+		x += 4; print x;
+		x -= 1; print x;
+		x *= 3; print x;
+		x /= 4; print x;
+		x %= 2; print x;
+		var s = "a";
+		s += "b";
+		print s;
+		var total = 0;
+		for (var i = 1; i <= 4; i++) total += i;
+		print total;`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, "5\n4\n12\n3\n1\nab\n10\n")
+}
+
+func TestIncrementAndDecrement(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var i = 0;
+		print i++;
+		print i;
+		print ++i;
+		print i--;
+		print --i;`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, "0\n1\n2\n2\n0\n")
+}
+
+func TestIndexAssignment(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		var calls = 0;
+		fun first() { calls++; return 0; }
+		var xs = [1, 2, 3];
+		xs[1] = 20;
+		xs[first()] += 10;
+		print xs[first()]++;
+		xs[2]--;
+		print xs;
+		print calls;
+		var counts = jsonParse("{}");
+		counts["a"] = 1;
+		counts["a"] += 1;
+		print counts;`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, "11\n[12, 20, 2]\n2\n{a: 2}\n")
+}
+
+func TestAssignmentErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`missing += 1;`, "undefined variable: missing"},
+		{`var s = "a"; s++;`, "Operand must be a number."},
+		{`var s = "a"; s -= 1;`, "Operands must be numbers."},
+		{`var xs = [1]; xs[1] = 2;`, "Index out of range."},
+		{`var xs = [1]; xs["a"] += 2;`, "Index must be an integer."},
+		{`var s = "abc"; s[0] = "x";`, "Only lists and maps support index assignment."},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestConditionalExpressions(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		fun sign(n) { return n > 0 ? "positive" : n < 0 ? "negative" : "zero"; }
+		print sign(3);
+		print sign(-3);
+		print sign(0);
+		fun boom() { print "evaluated"; return 1; }
+		print true ? "lazy" : boom();
+		print nil ?? "default";
+		print false ?? "default";
+		print 0 ?? boom();
+		print false ?: "elvis";
+		print "set" ?: boom();
+		print nil ?? nil ?? 3;`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, "positive\nnegative\nzero\nlazy\ndefault\nfalse\n0\nelvis\nset\n3\n")
+
+	var errOut bytes.Buffer
+	evaluated := testEval(t, `-"a" ? 1 : 2;`, &stdout, &errOut)
+	testErrorObject(t, evaluated, "Operand must be a number.")
+}
+
+func TestAssertions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`assert(1 > 2);`, "Assertion failed"},
+		{`assert(nil, "custom");`, "Assertion failed: custom"},
+		{`assertEqual(1 + 1, 3);`, "assertEqual failed\n  expected: 3\n  actual:   2"},
+		{`assertEqual("1", 1, "types");`, "assertEqual failed: types\n  expected: 1\n  actual:   \"1\""},
+		{`assertEqual([1, 2], [1, 3]);`, "assertEqual failed\n  expected: [1, 3]\n  actual:   [1, 2]"},
+		{`assertEqual([1], ["1"]);`, "assertEqual failed\n  expected: [\"1\"]\n  actual:   [1]"},
+		{`assertEqual(["a, b"], ["a", "b"]);`, "assertEqual failed\n  expected: [\"a\", \"b\"]\n  actual:   [\"a, b\"]"},
+		{`assertEqual(jsonParse("{}"), jsonParse("[]"));`, "assertEqual failed\n  expected: []\n  actual:   {}"},
+		{`fun f() {} assertEqual(f(), 1);`, "assertEqual failed\n  expected: 1\n  actual:   nil"},
+		{"assertEqual(\"a\nb\nc\", \"a\nx\nc\");", "assertEqual failed\n  --- expected\n  +++ actual\n    a\n  - x\n  + b\n    c"},
+		{`fun ok() { return 1; } assertThrows(ok);`, "assertThrows failed: expected an error but none was thrown."},
+		{`fun bad() { throw "x"; } assertThrows(bad, "y");`, `assertThrows failed: expected error "y" but got "x".`},
+		{`assertThrows(1);`, "assertThrows() expects argument 1 to be a function."},
+		{`assert();`, "Expected 1 or 2 arguments but got 0."},
+		{`fun f(a) {} assertThrows(f, "Expected 1 arguments but got 0.");
+		  assertEqual(1, 2);`, "assertEqual failed\n  expected: 2\n  actual:   1"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		evaluated := testEval(t, tt.input, &stdout, &stderr)
+		testErrorObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestPassingAssertions(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	testEval(t, `
+		assert(true);
+		assertEqual("a" + "b", "ab");
+		assertEqual([1, [2]], [1, [2]]);
+		fun void() {}
+		assertEqual(void(), nil);
+		fun boom() { throw "boom"; }
+		var err = assertThrows(boom, "boom");
+		print err.message;
+		test "skipped outside the test command" { print "never"; }
+		print "done";`, &stdout, &stderr)
+
+	testStderr(t, stderr, "")
+	testStdout(t, stdout, "boom\ndone\n")
+}
+
+func TestCoverage(t *testing.T) {
+	dir := writeModuleFiles(t, map[string]string{
+		"main.lox": `import "lib.lox" as lib;
+var n = 0;
+while (n < 2) n = n + 1;
+for (var i = 0; i < 1; i = i + 1) {}
+if (n == 2 or lib.never()) print "two"; else print "other";
+print n > 5 ? "big" : "small";
+print false and lib.never();`,
+		"lib.lox": `fun never() { return true; }`,
+	})
+	path := filepath.Join(dir, "main.lox")
+	input, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	p := parser.New(lexer.New(string(input)))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	var stdout, stderr bytes.Buffer
+	var out, errOut io.Writer = &stdout, &stderr
+	e := NewEvaluator(&out, &errOut)
+	e.ScriptPath = path
+	e.Coverage = coverage.New()
+	e.Coverage.Register(path, program)
+	e.Eval(program, object.NewEnvironment())
+	testStderr(t, stderr, "")
+
+	if len(e.Coverage.Files) != 2 {
+		t.Fatalf("expected the imported module to be registered, got %d files", len(e.Coverage.Files))
+	}
+	main, lib := e.Coverage.Files[0], e.Coverage.Files[1]
+
+	var counts []string
+	for _, stmt := range main.Statements {
+		counts = append(counts, strconv.Itoa(stmt.Line)+":"+strconv.Itoa(stmt.Count))
+	}
+	expected := "1:1 2:1 3:1 3:2 4:1 4:1 4:1 5:1 5:1 5:0 6:1 7:1"
+	if got := strings.Join(counts, " "); got != expected {
+		t.Errorf("expected statement counts %s, got %s", expected, got)
+	}
+
+	var branches []string
+	for _, branch := range main.Branches {
+		branches = append(branches, branch.Kind+":"+strconv.Itoa(branch.True)+"/"+strconv.Itoa(branch.False))
+	}
+	expected = "while:2/1 for:1/1 if:1/0 or:1/0 conditional:0/1 and:0/1"
+	if got := strings.Join(branches, " "); got != expected {
+		t.Errorf("expected branch outcomes %s, got %s", expected, got)
+	}
+
+	if len(lib.Statements) != 2 || lib.Statements[0].Count != 1 || lib.Statements[1].Count != 0 {
+		t.Errorf("unexpected module coverage: %+v %+v", lib.Statements[0], lib.Statements[1])
+	}
+}
+
+func TestProfiler(t *testing.T) {
+	input := `fun fib(n) {
+  if (n < 2) return n;
+  return fib(n - 1) + fib(n - 2);
+}
+fun run() {
+  print fib(5);
+  len("native calls are not profiled");
+}
+run();
+run();`
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	var stdout, stderr bytes.Buffer
+	var out, errOut io.Writer = &stdout, &stderr
+	e := NewEvaluator(&out, &errOut)
+	e.Profiler = profile.New()
+	e.Profiler.Start()
+	e.Eval(program, object.NewEnvironment())
+	e.Profiler.Stop()
+	testStderr(t, stderr, "")
+
+	calls := map[string]int{}
+	for _, stats := range e.Profiler.Functions() {
+		calls[stats.Func.String()] = stats.Calls
+	}
+	expected := map[string]int{"<script>": 1, "run:5": 2, "fib:1": 30}
+	if len(calls) != len(expected) {
+		t.Errorf("expected functions %v, got %v", expected, calls)
+	}
+	for name, want := range expected {
+		if calls[name] != want {
+			t.Errorf("expected %d calls to %s, got %d", want, name, calls[name])
+		}
+	}
+
+	deepest := 0
+	for _, stack := range e.Profiler.Stacks() {
+		deepest = max(deepest, len(stack.Frames))
+	}
+	if deepest != 7 {
+		t.Errorf("expected the deepest stack to have 7 frames, got %d", deepest)
+	}
+}
+
+func testTrace(t *testing.T, input string, funcs []string) string {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	var stdout, stderr, trace bytes.Buffer
+	var out, errOut io.Writer = &stdout, &stderr
+	e := NewEvaluator(&out, &errOut)
+	e.Tracer = NewTracer(&trace, funcs)
+	e.Eval(program, object.NewEnvironment())
+	return trace.String()
+}
+
+func TestTrace(t *testing.T) {
+	input := `fun add(a, b) {
+  var sum = a + b;
+  return sum;
+}
+fun fail() { throw "no"; }
+var xs = [1];
+xs[0] += add(2, 3);
+try { fail(); } catch (e) {}
+var name = "x";
+name = "y";`
+
+	expected := `[line 1] fun add (a, b) {var sum = (+ a b);return sum;}
+[line 5] fun fail () {throw no;}
+[line 6] var xs = [1.0];
+var xs = [1]
+[line 7] xs[0.0] += add(2.0, 3.0);
+call add(2, 3)
+  [line 2] var sum = (+ a b);
+  var sum = 5
+  [line 3] return sum;
+add returned 5
+xs[0.0] = 6
+[line 8] try {fail()} catch (e) {}
+[line 8] fail()
+call fail()
+  [line 5] throw no;
+fail threw: no
+[line 9] var name = x;
+var name = "x"
+[line 10] name = y;
+name = "y"
+`
+	if got := testTrace(t, input, nil); got != expected {
+		t.Errorf("expected trace:\n%s\ngot:\n%s", expected, got)
+	}
+
+	expected = `call fail()
+  [line 5] throw no;
+fail threw: no
+`
+	if got := testTrace(t, input, []string{"fail"}); got != expected {
+		t.Errorf("expected filtered trace:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestTraceTruncatesLongStatements(t *testing.T) {
+	got := testTrace(t, `print "`+strings.Repeat("a", 100)+`";`, nil)
+	if len(got) > 80 || !strings.HasSuffix(got, "...\n") {
+		t.Errorf("expected a truncated statement, got %q", got)
+	}
+}
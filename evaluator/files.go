@@ -0,0 +1,112 @@
+package evaluator
+
+import (
+	"os"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+// File natives report failures as runtime errors carrying the OS error
+// message, so scripts can recover from them with try/catch.
+var fileBuiltins = map[string]*object.NativeFunction{
+	"readFile": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("readFile", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			content, err := os.ReadFile(args[0].(*object.String).Value)
+			if err != nil {
+				return newError("%s", err)
+			}
+			return &object.String{Value: string(content)}
+		},
+	},
+	"writeFile": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("writeFile", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return writeFile(args[0].(*object.String).Value, args[1].(*object.String).Value, os.O_TRUNC)
+		},
+	},
+	"appendFile": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("appendFile", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return writeFile(args[0].(*object.String).Value, args[1].(*object.String).Value, os.O_APPEND)
+		},
+	},
+	"readLines": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("readLines", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			content, err := os.ReadFile(args[0].(*object.String).Value)
+			if err != nil {
+				return newError("%s", err)
+			}
+			text := strings.TrimSuffix(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+			if text == "" {
+				return &object.List{}
+			}
+			return stringList(strings.Split(text, "\n"))
+		},
+	},
+	"exists": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("exists", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			_, err := os.Stat(args[0].(*object.String).Value)
+			return nativeToBoolean(err == nil)
+		},
+	},
+	"listDir": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("listDir", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			entries, err := os.ReadDir(args[0].(*object.String).Value)
+			if err != nil {
+				return newError("%s", err)
+			}
+			names := make([]string, len(entries))
+			for i, entry := range entries {
+				names[i] = entry.Name()
+			}
+			return stringList(names)
+		},
+	},
+	"removeFile": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("removeFile", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			if err := os.Remove(args[0].(*object.String).Value); err != nil {
+				return newError("%s", err)
+			}
+			return NIL
+		},
+	},
+}
+
+func init() {
+	registerBuiltins(fileBuiltins)
+}
+
+func writeFile(path, content string, mode int) object.Object {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|mode, 0644)
+	if err != nil {
+		return newError("%s", err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return newError("%s", err)
+	}
+	if err := f.Close(); err != nil {
+		return newError("%s", err)
+	}
+	return NIL
+}
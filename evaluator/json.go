@@ -0,0 +1,183 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+// JSON objects decode to maps that keep the key order of the source text,
+// and encoding writes map keys back in that same order.
+var jsonBuiltins = map[string]*object.NativeFunction{
+	"jsonParse": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("jsonParse", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return parseJSON(args[0].(*object.String).Value)
+		},
+	},
+	"jsonStringify": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newError("Expected 1 or 2 arguments but got %d.", len(args))
+			}
+			indent := 0
+			if len(args) == 2 {
+				n, ok := toInt(args[1])
+				if !ok || n < 0 {
+					return newError("jsonStringify() indent must be a non-negative integer.")
+				}
+				indent = n
+			}
+
+			var out bytes.Buffer
+			if err := encodeJSON(&out, args[0]); err != nil {
+				return err
+			}
+			if indent == 0 {
+				return &object.String{Value: out.String()}
+			}
+
+			var indented bytes.Buffer
+			json.Indent(&indented, out.Bytes(), "", strings.Repeat(" ", indent))
+			return &object.String{Value: indented.String()}
+		},
+	},
+	"keys": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("keys", args, object.MAP_OBJ); err != nil {
+				return err
+			}
+			return stringList(args[0].(*object.Map).Keys)
+		},
+	},
+}
+
+func init() {
+	registerBuiltins(jsonBuiltins)
+}
+
+func parseJSON(input string) object.Object {
+	dec := json.NewDecoder(strings.NewReader(input))
+	value, err := decodeJSONValue(dec)
+	if err == nil {
+		if _, err = dec.Token(); err == io.EOF {
+			return value
+		}
+		if err == nil {
+			err = errors.New("unexpected data after top-level value")
+		}
+	}
+
+	offset := dec.InputOffset()
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.Offset
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = errors.New("unexpected end of JSON input")
+	}
+	return newError("jsonParse() invalid JSON at offset %d: %s", offset, err)
+}
+
+func decodeJSONValue(dec *json.Decoder) (object.Object, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '[' {
+			list := &object.List{}
+			for dec.More() {
+				el, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				list.Elements = append(list.Elements, el)
+			}
+			_, err := dec.Token()
+			return list, err
+		}
+
+		m := object.NewMap()
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(key.(string), value)
+		}
+		_, err := dec.Token()
+		return m, err
+	case string:
+		return &object.String{Value: tok}, nil
+	case float64:
+		return &object.Number{Value: tok}, nil
+	case bool:
+		return nativeToBoolean(tok), nil
+	}
+	return NIL, nil
+}
+
+func encodeJSON(out *bytes.Buffer, obj object.Object) *object.Error {
+	switch obj := obj.(type) {
+	case nil, *object.Nil:
+		out.WriteString("null")
+	case *object.Boolean:
+		out.WriteString(strconv.FormatBool(obj.Value))
+	case *object.Number:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return newError("jsonStringify() cannot encode %s.", obj.Inspect())
+		}
+		out.WriteString(strconv.FormatFloat(obj.Value, 'f', -1, 64))
+	case *object.String:
+		encodeJSONString(out, obj.Value)
+	case *object.List:
+		out.WriteByte('[')
+		for i, el := range obj.Elements {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := encodeJSON(out, el); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+	case *object.Map:
+		out.WriteByte('{')
+		for i, key := range obj.Keys {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			encodeJSONString(out, key)
+			out.WriteByte(':')
+			if err := encodeJSON(out, obj.Pairs[key]); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+	default:
+		return newError("jsonStringify() cannot encode a %s.", typeName(obj.Type()))
+	}
+	return nil
+}
+
+func encodeJSONString(out *bytes.Buffer, s string) {
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	out.Truncate(out.Len() - 1) // drop the newline Encode appends
+}
@@ -0,0 +1,119 @@
+package evaluator
+
+import (
+	"math"
+
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+var mathBuiltins = map[string]*object.NativeFunction{
+	"floor": unaryMathFunction("floor", math.Floor),
+	"ceil":  unaryMathFunction("ceil", math.Ceil),
+	"round": unaryMathFunction("round", math.Round),
+	"abs":   unaryMathFunction("abs", math.Abs),
+	"sqrt":  unaryMathFunction("sqrt", math.Sqrt),
+	"sin":   unaryMathFunction("sin", math.Sin),
+	"cos":   unaryMathFunction("cos", math.Cos),
+	"tan":   unaryMathFunction("tan", math.Tan),
+	"log":   unaryMathFunction("log", math.Log),
+	"exp":   unaryMathFunction("exp", math.Exp),
+	"pow": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("pow", args, object.NUMBER_OBJ, object.NUMBER_OBJ); err != nil {
+				return err
+			}
+			return &object.Number{Value: math.Pow(args[0].(*object.Number).Value, args[1].(*object.Number).Value)}
+		},
+	},
+	"min": variadicMathFunction("min", math.Min),
+	"max": variadicMathFunction("max", math.Max),
+	"isNaN": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("isNaN", args, object.NUMBER_OBJ); err != nil {
+				return err
+			}
+			return nativeToBoolean(math.IsNaN(args[0].(*object.Number).Value))
+		},
+	},
+	"div": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("div", args, object.NUMBER_OBJ, object.NUMBER_OBJ); err != nil {
+				return err
+			}
+			quotient, _, err := floorDivMod(args[0].(*object.Number).Value, args[1].(*object.Number).Value)
+			if err != nil {
+				return err
+			}
+			return &object.Number{Value: quotient}
+		},
+	},
+	"mod": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("mod", args, object.NUMBER_OBJ, object.NUMBER_OBJ); err != nil {
+				return err
+			}
+			_, remainder, err := floorDivMod(args[0].(*object.Number).Value, args[1].(*object.Number).Value)
+			if err != nil {
+				return err
+			}
+			return &object.Number{Value: remainder}
+		},
+	},
+}
+
+var mathConstants = map[string]object.Object{
+	"pi":  &object.Number{Value: math.Pi},
+	"inf": &object.Number{Value: math.Inf(1)},
+	"nan": &object.Number{Value: math.NaN()},
+}
+
+func init() {
+	registerBuiltins(mathBuiltins)
+	for name, value := range mathConstants {
+		builtins[name] = value
+	}
+}
+
+func unaryMathFunction(name string, fn func(float64) float64) *object.NativeFunction {
+	return &object.NativeFunction{
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs(name, args, object.NUMBER_OBJ); err != nil {
+				return err
+			}
+			return &object.Number{Value: fn(args[0].(*object.Number).Value)}
+		},
+	}
+}
+
+func variadicMathFunction(name string, fn func(float64, float64) float64) *object.NativeFunction {
+	return &object.NativeFunction{
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return newError("Expected at least 1 arguments but got 0.")
+			}
+			result := math.NaN()
+			for i, arg := range args {
+				num, ok := arg.(*object.Number)
+				if !ok {
+					return newError("%s() expects argument %d to be a number.", name, i+1)
+				}
+				if i == 0 {
+					result = num.Value
+				} else {
+					result = fn(result, num.Value)
+				}
+			}
+			return &object.Number{Value: result}
+		},
+	}
+}
+
+// floorDivMod divides rounding towards negative infinity, so the remainder
+// always has the sign of the divisor and a == q*b + r holds.
+func floorDivMod(a, b float64) (float64, float64, *object.Error) {
+	if b == 0 {
+		return 0, 0, newError("Division by zero.")
+	}
+	quotient := math.Floor(a / b)
+	return quotient, a - quotient*b, nil
+}
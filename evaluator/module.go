@@ -0,0 +1,133 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/ast"
+	"github.com/codecrafters-io/interpreter-starter-go/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+	"github.com/codecrafters-io/interpreter-starter-go/parser"
+)
+
+func (e *Evaluator) evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object {
+	path, ok := e.resolveModule(node.Path, env)
+	if !ok {
+		return newError("Could not find module '%s'.", node.Path)
+	}
+
+	loaded := e.loadModule(path)
+	if isError(loaded) {
+		return loaded
+	}
+	module := loaded.(*object.Module)
+
+	if node.Alias != nil {
+		env.Define(node.Alias.Value, module)
+		return nil
+	}
+
+	for _, name := range node.Names {
+		value, ok := module.GetProperty(name.Value)
+		if !ok {
+			return newError("Module '%s' has no exported name '%s'.", module.Name, name.Value)
+		}
+		env.Define(name.Value, value)
+	}
+	return nil
+}
+
+// modulePathKey names the binding that records which file a module
+// environment was loaded from. It is not a valid identifier, so scripts
+// cannot read or overwrite it.
+const modulePathKey = "<module path>"
+
+// importerPath returns the file whose code runs in env: the module that
+// defined it, or the top-level script.
+func (e *Evaluator) importerPath(env *object.Environment) string {
+	if path, ok := env.Get(modulePathKey); ok {
+		return path.(*object.String).Value
+	}
+	return e.ScriptPath
+}
+
+// resolveModule finds the file an import refers to. Relative paths are tried
+// next to the importing file first and then in each module search path.
+func (e *Evaluator) resolveModule(path string, env *object.Environment) (string, bool) {
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		candidates = []string{filepath.Join(filepath.Dir(e.importerPath(env)), path)}
+		for _, dir := range e.ModulePaths {
+			candidates = append(candidates, filepath.Join(dir, path))
+		}
+	}
+
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		abs, err := filepath.Abs(candidate)
+		if err != nil {
+			return "", false
+		}
+		return abs, true
+	}
+	return "", false
+}
+
+// loadModule evaluates a module file into its own environment. Each file is
+// evaluated at most once; later imports share the cached module.
+func (e *Evaluator) loadModule(path string) object.Object {
+	if module, ok := e.modules[path]; ok {
+		return module
+	}
+
+	for i, loading := range e.importStack {
+		if loading == path {
+			cycle := []string{}
+			for _, p := range e.importStack[i:] {
+				cycle = append(cycle, filepath.Base(p))
+			}
+			cycle = append(cycle, filepath.Base(path))
+			return newError("Import cycle detected: %s.", strings.Join(cycle, " -> "))
+		}
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return newError("Could not read module '%s': %s", path, err)
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		return newError("Could not parse module '%s':\n%s", filepath.Base(path), strings.Join(p.Errors(), "\n"))
+	}
+
+	if e.Coverage != nil {
+		e.Coverage.Register(path, program)
+	}
+
+	module := &object.Module{
+		Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Path: path,
+		Env:  object.NewEnvironment(),
+	}
+	module.Env.Define(modulePathKey, &object.String{Value: path})
+
+	line := e.line
+	e.importStack = append(e.importStack, path)
+	defer func() {
+		e.line = line
+		e.importStack = e.importStack[:len(e.importStack)-1]
+	}()
+
+	if result := e.evalBlockStatement(program.Statements, module.Env); isError(result) {
+		return result
+	}
+
+	e.modules[path] = module
+	return module
+}
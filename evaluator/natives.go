@@ -0,0 +1,51 @@
+package evaluator
+
+import (
+	"math"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+// registerBuiltins adds a group of natives to the global builtins table.
+func registerBuiltins(natives map[string]*object.NativeFunction) {
+	for name, fn := range natives {
+		builtins[name] = fn
+	}
+}
+
+// checkArgs validates the number and types of the arguments passed to a
+// native function, returning a runtime error describing the first mismatch.
+func checkArgs(name string, args []object.Object, types ...object.ObjectType) *object.Error {
+	if len(args) != len(types) {
+		return newError("Expected %d arguments but got %d.", len(types), len(args))
+	}
+	for i, t := range types {
+		if args[i] == nil {
+			args[i] = NIL
+		}
+		if args[i].Type() != t {
+			return newError("%s() expects argument %d to be a %s.", name, i+1, typeName(t))
+		}
+	}
+	return nil
+}
+
+func typeName(t object.ObjectType) string {
+	return strings.ToLower(strings.ReplaceAll(string(t), "_", " "))
+}
+
+// maxSafeInt is the largest magnitude up to which every integer is exactly
+// representable as a float64.
+const maxSafeInt = 1 << 53
+
+// toInt converts a number with no fractional part to an int. Numbers beyond
+// ±2^53 are rejected, as they may not be the integer they were written as
+// and converting them to int is not well defined.
+func toInt(obj object.Object) (int, bool) {
+	num, ok := obj.(*object.Number)
+	if !ok || num.Value != math.Trunc(num.Value) || math.Abs(num.Value) > maxSafeInt {
+		return 0, false
+	}
+	return int(num.Value), true
+}
@@ -0,0 +1,83 @@
+package evaluator
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+// SetArgs exposes the command-line arguments that follow the script name to
+// the script as the `args` list.
+func (e *Evaluator) SetArgs(args []string) {
+	e.natives["args"] = stringList(args)
+}
+
+// readLine reads one line from Stdin without its line terminator. It
+// returns false once the input is exhausted.
+func (e *Evaluator) readLine() (string, bool, error) {
+	if e.stdin == nil {
+		e.stdin = bufio.NewReader(e.Stdin)
+	}
+
+	line, err := e.stdin.ReadString('\n')
+	if err == io.EOF {
+		return strings.TrimSuffix(line, "\r"), line != "", nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), true, nil
+}
+
+func (e *Evaluator) processBuiltins() map[string]*object.NativeFunction {
+	readLine := func(name string) object.Object {
+		line, ok, err := e.readLine()
+		if err != nil {
+			return newError("%s() failed: %s", name, err)
+		}
+		if !ok {
+			return NIL
+		}
+		return &object.String{Value: line}
+	}
+
+	return map[string]*object.NativeFunction{
+		"readLine": {
+			Fn: func(args ...object.Object) object.Object {
+				if err := checkArgs("readLine", args); err != nil {
+					return err
+				}
+				return readLine("readLine")
+			},
+		},
+		"input": {
+			Fn: func(args ...object.Object) object.Object {
+				if len(args) > 1 {
+					return newError("Expected at most 1 arguments but got %d.", len(args))
+				}
+				if len(args) == 1 {
+					io.WriteString(e.stdout, args[0].Inspect())
+				}
+				return readLine("input")
+			},
+		},
+		"exit": {
+			Fn: func(args ...object.Object) object.Object {
+				if len(args) > 1 {
+					return newError("Expected at most 1 arguments but got %d.", len(args))
+				}
+				code := 0
+				if len(args) == 1 {
+					n, ok := toInt(args[0])
+					if !ok {
+						return newError("exit() expects an integer status code.")
+					}
+					code = n
+				}
+				return &object.Exit{Code: code}
+			},
+		},
+	}
+}
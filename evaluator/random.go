@@ -0,0 +1,71 @@
+package evaluator
+
+import (
+	"math/rand"
+
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+// Seed resets the evaluator's random number generator so that the random
+// natives produce a reproducible sequence.
+func (e *Evaluator) Seed(seed int64) {
+	e.rand = rand.New(rand.NewSource(seed))
+}
+
+func (e *Evaluator) randomBuiltins() map[string]*object.NativeFunction {
+	return map[string]*object.NativeFunction{
+		"random": {
+			Fn: func(args ...object.Object) object.Object {
+				if err := checkArgs("random", args); err != nil {
+					return err
+				}
+				return &object.Number{Value: e.rand.Float64()}
+			},
+		},
+		"randomInt": {
+			Fn: func(args ...object.Object) object.Object {
+				if err := checkArgs("randomInt", args, object.NUMBER_OBJ, object.NUMBER_OBJ); err != nil {
+					return err
+				}
+				lo, okLo := toInt(args[0])
+				hi, okHi := toInt(args[1])
+				if !okLo || !okHi {
+					return newError("randomInt() bounds must be integers.")
+				}
+				if lo > hi {
+					return newError("randomInt() lower bound must not exceed upper bound.")
+				}
+				span := hi - lo + 1
+				if span <= 0 {
+					return newError("randomInt() range is too large.")
+				}
+				return &object.Number{Value: float64(lo + e.rand.Intn(span))}
+			},
+		},
+		"shuffle": {
+			Fn: func(args ...object.Object) object.Object {
+				if err := checkArgs("shuffle", args, object.LIST_OBJ); err != nil {
+					return err
+				}
+				elements := args[0].(*object.List).Elements
+				e.rand.Shuffle(len(elements), func(i, j int) {
+					elements[i], elements[j] = elements[j], elements[i]
+				})
+				return args[0]
+			},
+		},
+		"seed": {
+			Fn: func(args ...object.Object) object.Object {
+				if err := checkArgs("seed", args, object.NUMBER_OBJ); err != nil {
+					return err
+				}
+				seed, ok := toInt(args[0])
+				if !ok {
+					return newError("seed() expects an integer.")
+				}
+				e.Seed(int64(seed))
+				return NIL
+			},
+		},
+	}
+}
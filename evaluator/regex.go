@@ -0,0 +1,134 @@
+package evaluator
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+// maxCachedRegexes bounds the regex cache, so programs that build many
+// distinct patterns at run time do not grow it without limit.
+const maxCachedRegexes = 256
+
+// regexCache holds recently compiled patterns so a pattern used inside a
+// loop is compiled only once. It evicts the least recently used pattern
+// when full, and is shared by every evaluator, hence the lock.
+var regexCache = struct {
+	sync.Mutex
+	patterns map[string]*list.Element // values are *regexp.Regexp
+	order    *list.List               // most recently used first
+}{patterns: make(map[string]*list.Element), order: list.New()}
+
+func compileRegex(pattern string) (*regexp.Regexp, *object.Error) {
+	regexCache.Lock()
+	defer regexCache.Unlock()
+
+	if el, ok := regexCache.patterns[pattern]; ok {
+		regexCache.order.MoveToFront(el)
+		return el.Value.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, newError("Invalid regex '%s': %s", pattern, err)
+	}
+	regexCache.patterns[pattern] = regexCache.order.PushFront(re)
+	if regexCache.order.Len() > maxCachedRegexes {
+		oldest := regexCache.order.Back()
+		regexCache.order.Remove(oldest)
+		delete(regexCache.patterns, oldest.Value.(*regexp.Regexp).String())
+	}
+	return re, nil
+}
+
+// regexArgs validates a (pattern, string) argument pair, where the pattern
+// may be a regex value or a string that is compiled through the cache.
+func regexArgs(name string, args []object.Object) (*regexp.Regexp, string, *object.Error) {
+	if len(args) != 2 {
+		return nil, "", newError("Expected 2 arguments but got %d.", len(args))
+	}
+	s, ok := args[1].(*object.String)
+	if !ok {
+		return nil, "", newError("%s() expects argument 2 to be a string.", name)
+	}
+	switch pattern := args[0].(type) {
+	case *object.Regex:
+		return pattern.Regexp, s.Value, nil
+	case *object.String:
+		re, err := compileRegex(pattern.Value)
+		return re, s.Value, err
+	}
+	return nil, "", newError("%s() expects argument 1 to be a regex or string.", name)
+}
+
+var regexBuiltins = map[string]*object.NativeFunction{
+	"regex": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("regex", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			re, err := compileRegex(args[0].(*object.String).Value)
+			if err != nil {
+				return err
+			}
+			return &object.Regex{Regexp: re}
+		},
+	},
+	"match": {
+		Fn: func(args ...object.Object) object.Object {
+			re, s, err := regexArgs("match", args)
+			if err != nil {
+				return err
+			}
+			return nativeToBoolean(re.MatchString(s))
+		},
+	},
+	"find": {
+		Fn: func(args ...object.Object) object.Object {
+			re, s, err := regexArgs("find", args)
+			if err != nil {
+				return err
+			}
+			loc := re.FindStringIndex(s)
+			if loc == nil {
+				return NIL
+			}
+			return &object.String{Value: s[loc[0]:loc[1]]}
+		},
+	},
+	"findAll": {
+		Fn: func(args ...object.Object) object.Object {
+			re, s, err := regexArgs("findAll", args)
+			if err != nil {
+				return err
+			}
+			return stringList(re.FindAllString(s, -1))
+		},
+	},
+	"captures": {
+		Fn: func(args ...object.Object) object.Object {
+			re, s, err := regexArgs("captures", args)
+			if err != nil {
+				return err
+			}
+			loc := re.FindStringSubmatchIndex(s)
+			if loc == nil {
+				return NIL
+			}
+			groups := &object.List{}
+			for i := 0; i < len(loc); i += 2 {
+				if loc[i] < 0 {
+					groups.Elements = append(groups.Elements, NIL)
+					continue
+				}
+				groups.Elements = append(groups.Elements, &object.String{Value: s[loc[i]:loc[i+1]]})
+			}
+			return groups
+		},
+	},
+}
+
+func init() {
+	registerBuiltins(regexBuiltins)
+}
@@ -0,0 +1,202 @@
+package evaluator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+// String natives operate on runes rather than bytes, so lengths and indexes
+// agree with what a reader counts in the source text.
+var stringBuiltins = map[string]*object.NativeFunction{
+	"len": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Expected 1 arguments but got %d.", len(args))
+			}
+			switch arg := args[0].(type) {
+			case *object.String:
+				return &object.Number{Value: float64(utf8.RuneCountInString(arg.Value))}
+			case *object.List:
+				return &object.Number{Value: float64(len(arg.Elements))}
+			case *object.Map:
+				return &object.Number{Value: float64(len(arg.Keys))}
+			}
+			return newError("len() expects a string, list or map.")
+		},
+	},
+	"substr": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) == 2 {
+				if err := checkArgs("substr", args, object.STRING_OBJ, object.NUMBER_OBJ); err != nil {
+					return err
+				}
+				args = append(args, &object.Number{Value: float64(utf8.RuneCountInString(args[0].(*object.String).Value))})
+			}
+			if err := checkArgs("substr", args, object.STRING_OBJ, object.NUMBER_OBJ, object.NUMBER_OBJ); err != nil {
+				return err
+			}
+			runes := []rune(args[0].(*object.String).Value)
+			start, ok := toInt(args[1])
+			if !ok {
+				return newError("substr() start must be an integer.")
+			}
+			end, ok := toInt(args[2])
+			if !ok {
+				return newError("substr() end must be an integer.")
+			}
+			if start < 0 || end > len(runes) || start > end {
+				return newError("substr() range out of bounds.")
+			}
+			return &object.String{Value: string(runes[start:end])}
+		},
+	},
+	"indexOf": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("indexOf", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			s := args[0].(*object.String).Value
+			idx := strings.Index(s, args[1].(*object.String).Value)
+			if idx < 0 {
+				return &object.Number{Value: -1}
+			}
+			return &object.Number{Value: float64(utf8.RuneCountInString(s[:idx]))}
+		},
+	},
+	"split": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("split", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			parts := strings.Split(args[0].(*object.String).Value, args[1].(*object.String).Value)
+			return stringList(parts)
+		},
+	},
+	"join": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("join", args, object.LIST_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			parts := []string{}
+			for _, el := range args[0].(*object.List).Elements {
+				parts = append(parts, el.Inspect())
+			}
+			return &object.String{Value: strings.Join(parts, args[1].(*object.String).Value)}
+		},
+	},
+	"upper": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("upper", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return &object.String{Value: strings.ToUpper(args[0].(*object.String).Value)}
+		},
+	},
+	"lower": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("lower", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return &object.String{Value: strings.ToLower(args[0].(*object.String).Value)}
+		},
+	},
+	"trim": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("trim", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return &object.String{Value: strings.TrimSpace(args[0].(*object.String).Value)}
+		},
+	},
+	"replace": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) == 3 {
+				if re, ok := args[1].(*object.Regex); ok {
+					if err := checkArgs("replace", args, object.STRING_OBJ, object.REGEX_OBJ, object.STRING_OBJ); err != nil {
+						return err
+					}
+					return &object.String{Value: re.Regexp.ReplaceAllString(args[0].(*object.String).Value, args[2].(*object.String).Value)}
+				}
+			}
+			if err := checkArgs("replace", args, object.STRING_OBJ, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			s := args[0].(*object.String).Value
+			return &object.String{Value: strings.ReplaceAll(s, args[1].(*object.String).Value, args[2].(*object.String).Value)}
+		},
+	},
+	"startsWith": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("startsWith", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return nativeToBoolean(strings.HasPrefix(args[0].(*object.String).Value, args[1].(*object.String).Value))
+		},
+	},
+	"endsWith": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("endsWith", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return nativeToBoolean(strings.HasSuffix(args[0].(*object.String).Value, args[1].(*object.String).Value))
+		},
+	},
+	"repeat": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("repeat", args, object.STRING_OBJ, object.NUMBER_OBJ); err != nil {
+				return err
+			}
+			count, ok := toInt(args[1])
+			if !ok || count < 0 {
+				return newError("repeat() count must be a non-negative integer.")
+			}
+			return &object.String{Value: strings.Repeat(args[0].(*object.String).Value, count)}
+		},
+	},
+	"chars": {
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkArgs("chars", args, object.STRING_OBJ); err != nil {
+				return err
+			}
+			return stringList(strings.Split(args[0].(*object.String).Value, ""))
+		},
+	},
+	"format": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return newError("Expected at least 1 arguments but got 0.")
+			}
+			format, ok := args[0].(*object.String)
+			if !ok {
+				return newError("format() expects argument 1 to be a string.")
+			}
+			parts := strings.Split(format.Value, "{}")
+			values := args[1:]
+			if len(parts)-1 != len(values) {
+				return newError("format() has %d placeholders but got %d values.", len(parts)-1, len(values))
+			}
+			var out strings.Builder
+			for i, part := range parts {
+				out.WriteString(part)
+				if i < len(values) {
+					out.WriteString(values[i].Inspect())
+				}
+			}
+			return &object.String{Value: out.String()}
+		},
+	},
+}
+
+func init() {
+	registerBuiltins(stringBuiltins)
+}
+
+func stringList(parts []string) *object.List {
+	elements := make([]object.Object, len(parts))
+	for i, part := range parts {
+		elements[i] = &object.String{Value: part}
+	}
+	return &object.List{Elements: elements}
+}
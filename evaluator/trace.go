@@ -0,0 +1,91 @@
+package evaluator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/ast"
+	"github.com/codecrafters-io/interpreter-starter-go/object"
+)
+
+// maxTracedStatement is the length at which statements are cut short in the
+// trace, so an if or loop does not print its whole body on one line.
+const maxTracedStatement = 60
+
+// Tracer logs a script as it runs: each statement with its line, each call
+// to a user function with its arguments and result, and each assignment.
+// Entries are indented by call depth.
+type Tracer struct {
+	w      io.Writer
+	funcs  map[string]bool // if set, only calls to these functions are traced
+	inside int             // active calls to functions in funcs
+}
+
+// NewTracer returns a tracer writing to w. If funcs is not empty, only calls
+// to the named functions, and everything they execute, are traced.
+func NewTracer(w io.Writer, funcs []string) *Tracer {
+	t := &Tracer{w: w, funcs: map[string]bool{}}
+	for _, name := range funcs {
+		t.funcs[name] = true
+	}
+	return t
+}
+
+// trace writes one entry at the current call depth.
+func (e *Evaluator) trace(depth int, format string, args ...any) {
+	t := e.Tracer
+	if len(t.funcs) > 0 && t.inside == 0 {
+		return
+	}
+	fmt.Fprintf(t.w, "%s%s\n", strings.Repeat("  ", depth), fmt.Sprintf(format, args...))
+}
+
+func (e *Evaluator) traceStatement(node ast.Node, line int) {
+	text := node.String()
+	if runes := []rune(text); len(runes) > maxTracedStatement {
+		text = string(runes[:maxTracedStatement]) + "..."
+	}
+	e.trace(len(e.frames), "[line %d] %s", line, text)
+}
+
+// traceCall and traceReturn are called inside the callee's frame, so the
+// call and its result line up with the caller's statements.
+func (e *Evaluator) traceCall(fn *object.Function, args []object.Object) {
+	if e.Tracer.funcs[fn.Name] {
+		e.Tracer.inside++
+	}
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = traceValue(arg)
+	}
+	e.trace(len(e.frames)-1, "call %s(%s)", fn.Name, strings.Join(values, ", "))
+}
+
+func (e *Evaluator) traceReturn(fn *object.Function, result object.Object) {
+	switch result := result.(type) {
+	case *object.Error:
+		e.trace(len(e.frames)-1, "%s threw: %s", fn.Name, result.Message)
+	case *object.Exit:
+		e.trace(len(e.frames)-1, "%s exited with code %d", fn.Name, result.Code)
+	default:
+		e.trace(len(e.frames)-1, "%s returned %s", fn.Name, traceValue(result))
+	}
+	if e.Tracer.funcs[fn.Name] {
+		e.Tracer.inside--
+	}
+}
+
+// traceAssign logs an assignment unless it failed.
+func (e *Evaluator) traceAssign(target string, value, result object.Object) {
+	if e.Tracer != nil && !isError(result) {
+		e.trace(len(e.frames), "%s = %s", target, traceValue(value))
+	}
+}
+
+func traceValue(obj object.Object) string {
+	if obj == nil {
+		return "nil"
+	}
+	return formatValue(obj)
+}
@@ -1,9 +1,10 @@
 package lexer
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/codecrafters-io/interpreter-starter-go/token"
 )
@@ -12,8 +13,20 @@ type Lexer struct {
 	input        string
 	position     int
 	readPosition int
-	ch           byte
+	ch           rune // current code point, 0 at end of input
 	line         int
+
+	// interpolations tracks the `${` expressions currently being lexed,
+	// innermost last.
+	interpolations []interpolation
+
+	// doc collects `///` comment lines until the next token is produced.
+	doc []string
+}
+
+type interpolation struct {
+	braces int // unmatched '{' seen inside the expression
+	triple bool
 }
 
 func New(input string) *Lexer {
@@ -22,22 +35,31 @@ func New(input string) *Lexer {
 	return l
 }
 
+// readChar advances to the next code point. Invalid UTF-8 decodes as
+// utf8.RuneError one byte at a time.
 func (l *Lexer) readChar() {
+	l.position = l.readPosition
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
-	} else {
-		l.ch = l.input[l.readPosition]
+		return
 	}
-	l.position = l.readPosition
-	l.readPosition++
+	r, size := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	l.ch = r
+	l.readPosition += size
 }
 
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
 	}
 
-	return l.input[l.readPosition]
+	r, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	return r
+}
+
+// current returns the source text of the current code point.
+func (l *Lexer) current() string {
+	return l.input[l.position:l.readPosition]
 }
 
 func (l *Lexer) skipWhitespace() {
@@ -49,19 +71,175 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
-func (l *Lexer) readString() token.Token {
-	startPos := l.position
-	for l.ch != '"' && l.ch != 0 {
+// readString lexes a string literal starting at the opening quote; start is
+// the position of the first character of the lexeme, which includes the `r`
+// prefix of raw strings. Triple-quoted strings drop a newline directly after
+// the opening quotes, and raw strings keep backslashes and `${` as written.
+func (l *Lexer) readString(start int, raw bool) token.Token {
+	triple := strings.HasPrefix(l.input[l.position:], `"""`)
+	if triple {
+		l.skip(3)
+	} else {
+		l.readChar()
+	}
+
+	tok := l.readStringBody(start, triple, raw)
+	if triple && (tok.Type == token.STRING || tok.Type == token.INTERPOLATION) {
+		tok.Literal = strings.TrimPrefix(strings.TrimPrefix(tok.Literal, "\r"), "\n")
+	}
+	return tok
+}
+
+// readStringBody lexes string contents up to the closing quotes or the next
+// `${`. In the latter case it returns an INTERPOLATION token and records the
+// open interpolation, so that the matching `}` resumes the string.
+func (l *Lexer) readStringBody(start int, triple, raw bool) token.Token {
+	var value strings.Builder
+	invalid := ""
+	interpolated := false
+	for {
+		if l.ch == 0 {
+			l.interpolations = nil
+			return token.New(token.UNTERMINATED_STRING, "", "", l.line)
+		}
+		if triple && strings.HasPrefix(l.input[l.position:], `"""`) {
+			l.skip(3)
+			break
+		}
+		if !triple && l.ch == '"' {
+			l.readChar()
+			break
+		}
+
+		if l.ch == '\\' && !raw {
+			decoded, ok := l.readEscape()
+			if !ok && invalid == "" {
+				invalid = decoded
+			}
+			value.WriteString(decoded)
+			continue
+		}
+
+		if l.ch == '$' && l.peekChar() == '{' && !raw {
+			l.skip(2)
+			l.interpolations = append(l.interpolations, interpolation{triple: triple})
+			interpolated = true
+			break
+		}
+
+		if l.ch == '\n' {
+			l.line++
+		}
+		value.WriteString(l.current())
 		l.readChar()
 	}
 
-	if l.ch == 0 {
-		return token.New(token.UNTERMINATED_STRING, "", "", l.line)
+	if invalid != "" {
+		return token.New(token.INVALID_ESCAPE, invalid, "null", l.line)
+	}
+	if interpolated {
+		return token.New(token.INTERPOLATION, l.input[start:l.position], value.String(), l.line)
 	}
+	return token.New(token.STRING, l.input[start:l.position], value.String(), l.line)
+}
 
+// readEscape decodes the escape sequence at the current backslash. When the
+// sequence is invalid it returns the offending source text and false,
+// leaving the character after the backslash to be lexed as part of the
+// string so that quotes and newlines are still accounted for.
+func (l *Lexer) readEscape() (string, bool) {
+	start := l.position
 	l.readChar()
-	s := l.input[startPos : l.position-1]
-	return token.New(token.STRING, fmt.Sprintf(`"%s"`, s), s, l.line)
+
+	switch l.ch {
+	case 'n':
+		l.readChar()
+		return "\n", true
+	case 't':
+		l.readChar()
+		return "\t", true
+	case 'r':
+		l.readChar()
+		return "\r", true
+	case '"':
+		l.readChar()
+		return `"`, true
+	case '$':
+		l.readChar()
+		return "$", true
+	case '\\':
+		l.readChar()
+		return `\`, true
+	case 'u':
+		l.readChar()
+		if l.ch != '{' {
+			return l.input[start:l.position], false
+		}
+		l.readChar()
+		digits := l.position
+		for isHexDigit(l.ch) && l.position-digits < 6 {
+			l.readChar()
+		}
+		if l.ch != '}' || l.position == digits {
+			return l.input[start:l.position], false
+		}
+		code, _ := strconv.ParseUint(l.input[digits:l.position], 16, 32)
+		l.readChar()
+		if !utf8.ValidRune(rune(code)) {
+			return l.input[start:l.position], false
+		}
+		return string(rune(code)), true
+	}
+
+	return l.input[start:l.readPosition], false
+}
+
+func (l *Lexer) skip(n int) {
+	for i := 0; i < n; i++ {
+		l.readChar()
+	}
+}
+
+// readLineComment skips a `//` comment. Comments starting with exactly
+// three slashes are doc comments and their text is kept for the next token.
+func (l *Lexer) readLineComment() {
+	start := l.position
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+
+	comment := l.input[start:l.position]
+	if strings.HasPrefix(comment, "///") && !strings.HasPrefix(comment, "////") {
+		text := strings.TrimPrefix(comment, "///")
+		text = strings.TrimPrefix(text, " ")
+		l.doc = append(l.doc, strings.TrimRight(text, "\r"))
+	}
+}
+
+// skipBlockComment skips a `/* */` comment, which may nest. It reports
+// false when the input ends before the comment is closed.
+func (l *Lexer) skipBlockComment() bool {
+	depth := 0
+	for {
+		switch {
+		case l.ch == 0:
+			return false
+		case l.ch == '/' && l.peekChar() == '*':
+			depth++
+			l.skip(2)
+		case l.ch == '*' && l.peekChar() == '/':
+			depth--
+			l.skip(2)
+			if depth == 0 {
+				return true
+			}
+		default:
+			if l.ch == '\n' {
+				l.line++
+			}
+			l.readChar()
+		}
+	}
 }
 
 func (l *Lexer) readNumber() token.Token {
@@ -91,53 +269,146 @@ func (l *Lexer) readNumber() token.Token {
 	return token.New(token.NUMBER, lexeme, literal, l.line)
 }
 
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+// isLetter reports whether ch may start an identifier: any Unicode letter
+// or an underscore.
+func isLetter(ch rune) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_' ||
+		ch >= utf8.RuneSelf && unicode.IsLetter(ch)
 }
 
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
 func (l *Lexer) readIdentifier() string {
 	startPos := l.position
-	for isLetter(l.ch) || isDigit(l.ch) {
+	for isLetter(l.ch) || isDigit(l.ch) || l.ch >= utf8.RuneSelf && unicode.IsDigit(l.ch) {
 		l.readChar()
 	}
 	return l.input[startPos:l.position]
 }
 
+// NextToken returns the next token, carrying any doc comment that
+// immediately precedes it.
 func (l *Lexer) NextToken() token.Token {
+	tok := l.nextToken()
+	if len(l.doc) > 0 {
+		tok.Doc = strings.Join(l.doc, "\n")
+		l.doc = nil
+	}
+	return tok
+}
+
+func (l *Lexer) nextToken() token.Token {
 	var tok token.Token
 	l.skipWhitespace()
 
 	switch l.ch {
 	case 0:
+		if len(l.interpolations) > 0 {
+			l.interpolations = nil
+			return token.New(token.UNTERMINATED_STRING, "", "", l.line)
+		}
 		tok = token.New(token.EOF, "\x00", "null", l.line)
 	case '(':
 		tok = token.New(token.LEFT_PAREN, string(l.ch), "null", l.line)
 	case ')':
 		tok = token.New(token.RIGHT_PAREN, string(l.ch), "null", l.line)
 	case '{':
+		if n := len(l.interpolations); n > 0 {
+			l.interpolations[n-1].braces++
+		}
 		tok = token.New(token.LEFT_BRACE, string(l.ch), "null", l.line)
 	case '}':
+		if n := len(l.interpolations); n > 0 {
+			if l.interpolations[n-1].braces == 0 {
+				open := l.interpolations[n-1]
+				l.interpolations = l.interpolations[:n-1]
+				start := l.position
+				l.readChar()
+				return l.readStringBody(start, open.triple, false)
+			}
+			l.interpolations[n-1].braces--
+		}
 		tok = token.New(token.RIGHT_BRACE, string(l.ch), "null", l.line)
+	case '[':
+		tok = token.New(token.LEFT_BRACKET, string(l.ch), "null", l.line)
+	case ']':
+		tok = token.New(token.RIGHT_BRACKET, string(l.ch), "null", l.line)
 	case '.':
 		tok = token.New(token.DOT, string(l.ch), "null", l.line)
 	case '*':
-		tok = token.New(token.STAR, string(l.ch), "null", l.line)
+		if l.peekChar() == '*' {
+			l.readChar()
+			tok = token.New(token.STAR_STAR, "**", "null", l.line)
+		} else if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.New(token.STAR_EQUAL, "*=", "null", l.line)
+		} else {
+			tok = token.New(token.STAR, string(l.ch), "null", l.line)
+		}
+	case '%':
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.New(token.PERCENT_EQUAL, "%=", "null", l.line)
+		} else {
+			tok = token.New(token.PERCENT, string(l.ch), "null", l.line)
+		}
+	case '&':
+		tok = token.New(token.AMPERSAND, string(l.ch), "null", l.line)
+	case '|':
+		tok = token.New(token.PIPE, string(l.ch), "null", l.line)
+	case '^':
+		tok = token.New(token.CARET, string(l.ch), "null", l.line)
+	case '~':
+		tok = token.New(token.TILDE, string(l.ch), "null", l.line)
 	case ',':
 		tok = token.New(token.COMMA, string(l.ch), "null", l.line)
 	case '+':
-		tok = token.New(token.PLUS, string(l.ch), "null", l.line)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.New(token.PLUS_EQUAL, "+=", "null", l.line)
+		} else if l.peekChar() == '+' {
+			l.readChar()
+			tok = token.New(token.PLUS_PLUS, "++", "null", l.line)
+		} else {
+			tok = token.New(token.PLUS, string(l.ch), "null", l.line)
+		}
 	case '-':
-		tok = token.New(token.MINUS, string(l.ch), "null", l.line)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.New(token.MINUS_EQUAL, "-=", "null", l.line)
+		} else if l.peekChar() == '-' {
+			l.readChar()
+			tok = token.New(token.MINUS_MINUS, "--", "null", l.line)
+		} else {
+			tok = token.New(token.MINUS, string(l.ch), "null", l.line)
+		}
 	case ';':
 		tok = token.New(token.SEMICOLON, string(l.ch), "null", l.line)
+	case ':':
+		tok = token.New(token.COLON, string(l.ch), "null", l.line)
+	case '?':
+		if l.peekChar() == '?' {
+			l.readChar()
+			tok = token.New(token.QUESTION_QUESTION, "??", "null", l.line)
+		} else if l.peekChar() == ':' {
+			l.readChar()
+			tok = token.New(token.QUESTION_COLON, "?:", "null", l.line)
+		} else {
+			tok = token.New(token.QUESTION, string(l.ch), "null", l.line)
+		}
 	case '<':
 		if l.peekChar() == '=' {
 			l.readChar()
 			tok = token.New(token.LESS_EQUAL, "<=", "null", l.line)
+		} else if l.peekChar() == '<' {
+			l.readChar()
+			tok = token.New(token.LESS_LESS, "<<", "null", l.line)
 		} else {
 			tok = token.New(token.LESS, string(l.ch), "null", l.line)
 		}
@@ -145,6 +416,9 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '=' {
 			l.readChar()
 			tok = token.New(token.GREATER_EQUAL, ">=", "null", l.line)
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.New(token.GREATER_GREATER, ">>", "null", l.line)
 		} else {
 			tok = token.New(token.GREATER, string(l.ch), "null", l.line)
 		}
@@ -164,29 +438,37 @@ func (l *Lexer) NextToken() token.Token {
 		}
 	case '/':
 		if l.peekChar() == '/' {
-			l.readChar()
-			for l.ch != '\n' && l.ch != 0 {
-				l.readChar()
+			l.readLineComment()
+			return l.nextToken()
+		} else if l.peekChar() == '*' {
+			if !l.skipBlockComment() {
+				return token.New(token.UNTERMINATED_COMMENT, "", "", l.line)
 			}
-
-			return l.NextToken()
+			return l.nextToken()
+		} else if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.New(token.SLASH_EQUAL, "/=", "null", l.line)
 		} else {
 			tok = token.New(token.SLASH, string(l.ch), "null", l.line)
 		}
 
 	case '"':
-		l.readChar()
-		return l.readString()
+		return l.readString(l.position, false)
 
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		return l.readNumber()
 
 	default:
+		if l.ch == 'r' && l.peekChar() == '"' {
+			start := l.position
+			l.readChar()
+			return l.readString(start, true)
+		}
 		if isLetter(l.ch) {
 			lexeme := l.readIdentifier()
 			return token.New(token.LookupIdent(lexeme), lexeme, "null", l.line)
 		}
-		tok = token.New(token.ILLEGAL, string(l.ch), "null", l.line)
+		tok = token.New(token.ILLEGAL, l.current(), "null", l.line)
 	}
 
 	l.readChar()
@@ -165,3 +165,259 @@ func TestReservedKeywords(t *testing.T) {
 
 	testLexTokens(t, input, expected)
 }
+
+func TestExceptionKeywords(t *testing.T) {
+	input := `try catch finally throw`
+
+	expected := []token.Token{
+		{Type: token.TRY, Lexeme: "try", Literal: "null", Line: 1},
+		{Type: token.CATCH, Lexeme: "catch", Literal: "null", Line: 1},
+		{Type: token.FINALLY, Lexeme: "finally", Literal: "null", Line: 1},
+		{Type: token.THROW, Lexeme: "throw", Literal: "null", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestBrackets(t *testing.T) {
+	input := `[1]`
+
+	expected := []token.Token{
+		{Type: token.LEFT_BRACKET, Lexeme: "[", Literal: "null", Line: 1},
+		{Type: token.NUMBER, Lexeme: "1", Literal: "1.0", Line: 1},
+		{Type: token.RIGHT_BRACKET, Lexeme: "]", Literal: "null", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestStringEscapes(t *testing.T) {
+	input := `"a\tb\n\"c\"\\ \u{e9}\u{1F600}" "\q" "\u{110000}" "\u{zz}" "ok"`
+
+	expected := []token.Token{
+		{Type: token.STRING, Lexeme: `"a\tb\n\"c\"\\ \u{e9}\u{1F600}"`, Literal: "a\tb\n\"c\"\\ é😀", Line: 1},
+		{Type: token.INVALID_ESCAPE, Lexeme: `\q`, Literal: "null", Line: 1},
+		{Type: token.INVALID_ESCAPE, Lexeme: `\u{110000}`, Literal: "null", Line: 1},
+		{Type: token.INVALID_ESCAPE, Lexeme: `\u{`, Literal: "null", Line: 1},
+		{Type: token.STRING, Lexeme: `"ok"`, Literal: "ok", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestMultilineAndRawStrings(t *testing.T) {
+	input := `"one
+two" r"C:\new" """
+  "x" \t
+y""" r"""\n""" r
+z`
+
+	expected := []token.Token{
+		{Type: token.STRING, Lexeme: "\"one\ntwo\"", Literal: "one\ntwo", Line: 2},
+		{Type: token.STRING, Lexeme: `r"C:\new"`, Literal: `C:\new`, Line: 2},
+		{Type: token.STRING, Lexeme: "\"\"\"\n  \"x\" \\t\ny\"\"\"", Literal: "  \"x\" \t\ny", Line: 4},
+		{Type: token.STRING, Lexeme: `r"""\n"""`, Literal: `\n`, Line: 4},
+		{Type: token.IDENTIFIER, Lexeme: "r", Literal: "null", Line: 4},
+		{Type: token.IDENTIFIER, Lexeme: "z", Literal: "null", Line: 5},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 5},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestUnterminatedTripleQuotedString(t *testing.T) {
+	input := `"""abc""`
+
+	expected := []token.Token{
+		{Type: token.UNTERMINATED_STRING, Lexeme: "", Literal: "", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestInterpolation(t *testing.T) {
+	input := `"a ${x + "${y}"} b ${ {} } c" "\${x}" r"${x}"`
+
+	expected := []token.Token{
+		{Type: token.INTERPOLATION, Lexeme: `"a ${`, Literal: "a ", Line: 1},
+		{Type: token.IDENTIFIER, Lexeme: "x", Literal: "null", Line: 1},
+		{Type: token.PLUS, Lexeme: "+", Literal: "null", Line: 1},
+		{Type: token.INTERPOLATION, Lexeme: `"${`, Literal: "", Line: 1},
+		{Type: token.IDENTIFIER, Lexeme: "y", Literal: "null", Line: 1},
+		{Type: token.STRING, Lexeme: `}"`, Literal: "", Line: 1},
+		{Type: token.INTERPOLATION, Lexeme: `} b ${`, Literal: " b ", Line: 1},
+		{Type: token.LEFT_BRACE, Lexeme: "{", Literal: "null", Line: 1},
+		{Type: token.RIGHT_BRACE, Lexeme: "}", Literal: "null", Line: 1},
+		{Type: token.STRING, Lexeme: `} c"`, Literal: " c", Line: 1},
+		{Type: token.STRING, Lexeme: `"\${x}"`, Literal: "${x}", Line: 1},
+		{Type: token.STRING, Lexeme: `r"${x}"`, Literal: "${x}", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestUnterminatedInterpolation(t *testing.T) {
+	input := `"a ${x`
+
+	expected := []token.Token{
+		{Type: token.INTERPOLATION, Lexeme: `"a ${`, Literal: "a ", Line: 1},
+		{Type: token.IDENTIFIER, Lexeme: "x", Literal: "null", Line: 1},
+		{Type: token.UNTERMINATED_STRING, Lexeme: "", Literal: "", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	input := `héllo 名前 _ñ1 x٣ "日本" ;`
+
+	expected := []token.Token{
+		{Type: token.IDENTIFIER, Lexeme: "héllo", Literal: "null", Line: 1},
+		{Type: token.IDENTIFIER, Lexeme: "名前", Literal: "null", Line: 1},
+		{Type: token.IDENTIFIER, Lexeme: "_ñ1", Literal: "null", Line: 1},
+		{Type: token.IDENTIFIER, Lexeme: "x٣", Literal: "null", Line: 1},
+		{Type: token.STRING, Lexeme: `"日本"`, Literal: "日本", Line: 1},
+		{Type: token.SEMICOLON, Lexeme: ";", Literal: "null", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestIllegalCodePoints(t *testing.T) {
+	input := "€ ٣\n😀\xff("
+
+	expected := []token.Token{
+		{Type: token.ILLEGAL, Lexeme: "€", Literal: "null", Line: 1},
+		{Type: token.ILLEGAL, Lexeme: "٣", Literal: "null", Line: 1},
+		{Type: token.ILLEGAL, Lexeme: "😀", Literal: "null", Line: 2},
+		{Type: token.ILLEGAL, Lexeme: "\xff", Literal: "null", Line: 2},
+		{Type: token.LEFT_PAREN, Lexeme: "(", Literal: "null", Line: 2},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 2},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestArithmeticAndBitwiseOperators(t *testing.T) {
+	input := `% ** * & | ^ ~ << <= < >> >= >`
+
+	expected := []token.Token{
+		{Type: token.PERCENT, Lexeme: "%", Literal: "null", Line: 1},
+		{Type: token.STAR_STAR, Lexeme: "**", Literal: "null", Line: 1},
+		{Type: token.STAR, Lexeme: "*", Literal: "null", Line: 1},
+		{Type: token.AMPERSAND, Lexeme: "&", Literal: "null", Line: 1},
+		{Type: token.PIPE, Lexeme: "|", Literal: "null", Line: 1},
+		{Type: token.CARET, Lexeme: "^", Literal: "null", Line: 1},
+		{Type: token.TILDE, Lexeme: "~", Literal: "null", Line: 1},
+		{Type: token.LESS_LESS, Lexeme: "<<", Literal: "null", Line: 1},
+		{Type: token.LESS_EQUAL, Lexeme: "<=", Literal: "null", Line: 1},
+		{Type: token.LESS, Lexeme: "<", Literal: "null", Line: 1},
+		{Type: token.GREATER_GREATER, Lexeme: ">>", Literal: "null", Line: 1},
+		{Type: token.GREATER_EQUAL, Lexeme: ">=", Literal: "null", Line: 1},
+		{Type: token.GREATER, Lexeme: ">", Literal: "null", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestCompoundAssignmentOperators(t *testing.T) {
+	input := `+= -= *= /= %= ++ -- + -`
+
+	expected := []token.Token{
+		{Type: token.PLUS_EQUAL, Lexeme: "+=", Literal: "null", Line: 1},
+		{Type: token.MINUS_EQUAL, Lexeme: "-=", Literal: "null", Line: 1},
+		{Type: token.STAR_EQUAL, Lexeme: "*=", Literal: "null", Line: 1},
+		{Type: token.SLASH_EQUAL, Lexeme: "/=", Literal: "null", Line: 1},
+		{Type: token.PERCENT_EQUAL, Lexeme: "%=", Literal: "null", Line: 1},
+		{Type: token.PLUS_PLUS, Lexeme: "++", Literal: "null", Line: 1},
+		{Type: token.MINUS_MINUS, Lexeme: "--", Literal: "null", Line: 1},
+		{Type: token.PLUS, Lexeme: "+", Literal: "null", Line: 1},
+		{Type: token.MINUS, Lexeme: "-", Literal: "null", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestConditionalOperators(t *testing.T) {
+	input := `? : ?? ?:`
+
+	expected := []token.Token{
+		{Type: token.QUESTION, Lexeme: "?", Literal: "null", Line: 1},
+		{Type: token.COLON, Lexeme: ":", Literal: "null", Line: 1},
+		{Type: token.QUESTION_QUESTION, Lexeme: "??", Literal: "null", Line: 1},
+		{Type: token.QUESTION_COLON, Lexeme: "?:", Literal: "null", Line: 1},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 1},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestBlockComments(t *testing.T) {
+	input := `( /* one
+/* nested
+*/ still comment */ )
+/**/ ; /* a */ /* b
+*/ !`
+
+	expected := []token.Token{
+		{Type: token.LEFT_PAREN, Lexeme: "(", Literal: "null", Line: 1},
+		{Type: token.RIGHT_PAREN, Lexeme: ")", Literal: "null", Line: 3},
+		{Type: token.SEMICOLON, Lexeme: ";", Literal: "null", Line: 4},
+		{Type: token.BANG, Lexeme: "!", Literal: "null", Line: 5},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 5},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	input := `; /* outer /* inner */
+`
+
+	expected := []token.Token{
+		{Type: token.SEMICOLON, Lexeme: ";", Literal: "null", Line: 1},
+		{Type: token.UNTERMINATED_COMMENT, Lexeme: "", Literal: "", Line: 2},
+		{Type: token.EOF, Lexeme: "\x00", Literal: "null", Line: 2},
+	}
+
+	testLexTokens(t, input, expected)
+}
+
+func TestDocComments(t *testing.T) {
+	input := `/// Adds numbers.
+///   Indented.
+fun
+// plain
+//// not a doc
+var
+/// Trailing.`
+
+	l := New(input)
+	tests := []struct {
+		typ token.TokenType
+		doc string
+	}{
+		{token.FUNCTION, "Adds numbers.\n  Indented."},
+		{token.VAR, ""},
+		{token.EOF, "Trailing."},
+	}
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.typ {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.typ, tok.Type)
+		}
+		if tok.Doc != tt.doc {
+			t.Errorf("tests[%d] - doc wrong. expected=%q, got=%q", i, tt.doc, tok.Doc)
+		}
+	}
+}
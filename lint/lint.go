@@ -0,0 +1,150 @@
+// Package lint reports likely mistakes in Lox programs that parse and run
+// without error, such as unused variables or code that can never execute.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/ast"
+)
+
+// Severity is how serious a diagnostic is.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+)
+
+// ParseSeverity converts a severity name to a Severity.
+func ParseSeverity(s string) (Severity, bool) {
+	switch Severity(s) {
+	case Error, Warning, Info:
+		return Severity(s), true
+	}
+	return "", false
+}
+
+// Rule describes one check.
+type Rule struct {
+	ID       string
+	Severity Severity // default severity
+	Summary  string
+}
+
+// Rules lists every check in the order they are documented.
+var Rules = []Rule{
+	{"unused-variable", Warning, "a local variable is declared but never read"},
+	{"unused-parameter", Warning, "a function parameter is never read"},
+	{"unreachable-code", Warning, "a statement follows a return or throw in the same block"},
+	{"shadowed-variable", Warning, "a declaration hides a variable of an enclosing scope"},
+	{"undeclared-assignment", Error, "a variable is assigned without ever being declared"},
+	{"self-comparison", Warning, "an expression is compared with itself"},
+	{"constant-condition", Warning, "a condition is a literal, so it always takes the same branch"},
+	{"inconsistent-return", Warning, "a function returns a value on some paths but not others"},
+}
+
+// LookupRule returns the rule with the given ID.
+func LookupRule(id string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Diagnostic is one finding.
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	Line     int
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d: %s: %s [%s]", d.Line, d.Severity, d.Message, d.Rule)
+}
+
+// Config selects which rules run and how severe their findings are.
+type Config struct {
+	Disabled map[string]bool     // rule IDs to skip
+	Severity map[string]Severity // overrides of default severities
+}
+
+// ignoreComment matches a suppression comment. It silences the listed rules,
+// or all rules when none are listed. A comment after code applies to its own
+// line; a comment on a line by itself applies to the next line.
+var ignoreComment = regexp.MustCompile(`//\s*lint:ignore\b([ \t]+[a-z-]+(?:[ \t]*,[ \t]*[a-z-]+)*)?`)
+
+// suppressions maps each line to the rules ignored on it. An empty rule
+// name ignores every rule.
+func suppressions(source string) map[int][]string {
+	ignored := map[int][]string{}
+	for i, line := range strings.Split(source, "\n") {
+		m := ignoreComment.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		rules := []string{""}
+		if m[2] >= 0 {
+			rules = strings.Split(strings.Join(strings.Fields(line[m[2]:m[3]]), ""), ",")
+		}
+		target := i + 1
+		if strings.TrimSpace(line[:m[0]]) == "" {
+			target++
+		}
+		ignored[target] = append(ignored[target], rules...)
+	}
+	return ignored
+}
+
+// Check lints program, whose text is source, and returns its diagnostics in
+// line order.
+func Check(program *ast.Program, source string, cfg Config) []Diagnostic {
+	l := &linter{}
+	newResolver(l).program(program)
+	checkSyntax(l, program)
+
+	ignored := suppressions(source)
+	var out []Diagnostic
+	for _, d := range l.diagnostics {
+		if cfg.Disabled[d.Rule] || isIgnored(ignored[d.Line], d.Rule) {
+			continue
+		}
+		if severity, ok := cfg.Severity[d.Rule]; ok {
+			d.Severity = severity
+		}
+		out = append(out, d)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Line < out[j].Line })
+	return out
+}
+
+func isIgnored(rules []string, id string) bool {
+	for _, rule := range rules {
+		if rule == "" || rule == id {
+			return true
+		}
+	}
+	return false
+}
+
+// linter collects diagnostics from the individual checks.
+type linter struct {
+	diagnostics []Diagnostic
+}
+
+func (l *linter) report(id string, line int, format string, args ...any) {
+	rule, _ := LookupRule(id)
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Rule:     id,
+		Severity: rule.Severity,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
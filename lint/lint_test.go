@@ -0,0 +1,152 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/interpreter-starter-go/lexer"
+	"github.com/codecrafters-io/interpreter-starter-go/parser"
+)
+
+func check(t *testing.T, input string, cfg Config) []string {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser has errors: %v", p.Errors())
+	}
+	var out []string
+	for _, d := range Check(program, input, cfg) {
+		out = append(out, d.String())
+	}
+	return out
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			"unused variable",
+			"var global = 1;\nfun f() {\n  var local = 1;\n  var _ignored = 2;\n  fun helper() {}\n}",
+			[]string{
+				"3: warning: Variable 'local' is declared but never used. [unused-variable]",
+				"5: warning: Function 'helper' is declared but never used. [unused-variable]",
+			},
+		},
+		{
+			"variables read by closures and compound assignments are used",
+			"fun f() {\n  var a = 1;\n  var b = 2;\n  var c = 3;\n  fun g() { return a; }\n  b += 1;\n  c++;\n  return g;\n}",
+			nil,
+		},
+		{
+			"assignment alone is not a use",
+			"fun f() {\n  var a = 1;\n  a = 2;\n}",
+			[]string{"2: warning: Variable 'a' is declared but never used. [unused-variable]"},
+		},
+		{
+			"unused parameter",
+			"fun f(a, b, _c) { return a; }",
+			[]string{"1: warning: Parameter 'b' is never used. [unused-parameter]"},
+		},
+		{
+			"unreachable code",
+			"fun f() {\n  return 1;\n  print 2;\n  print 3;\n}\nfun g() {\n  throw \"x\";\n  { print 1; }\n}",
+			[]string{
+				"3: warning: Unreachable code. [unreachable-code]",
+				"8: warning: Unreachable code. [unreachable-code]",
+			},
+		},
+		{
+			"shadowed variable",
+			"var x = 1;\nfun f(x) {\n  {\n    var x = 2;\n    print x;\n  }\n  return x;\n}\ntry {} catch (x) {}",
+			[]string{
+				"2: warning: 'x' shadows the variable declared on line 1. [shadowed-variable]",
+				"4: warning: 'x' shadows the variable declared on line 2. [shadowed-variable]",
+			},
+		},
+		{
+			"a local may shadow a parameter",
+			"fun f(a) {\n  var a = 1;\n  return a;\n}",
+			[]string{
+				"1: warning: Parameter 'a' is never used. [unused-parameter]",
+				"2: warning: 'a' shadows the variable declared on line 1. [shadowed-variable]",
+			},
+		},
+		{
+			"redeclaring a global is not shadowing",
+			"var x = 1;\nvar x = 2;\nprint x;",
+			nil,
+		},
+		{
+			"undeclared assignment",
+			"fun f() { later = 1; missing = 2; missing += 1; }\nvar later;",
+			[]string{
+				"1: error: Assignment to undeclared variable 'missing'. [undeclared-assignment]",
+				"1: error: Assignment to undeclared variable 'missing'. [undeclared-assignment]",
+			},
+		},
+		{
+			"self comparison",
+			"var a = 1;\nprint a == (a);\nprint (a + 1) < (a + 1);\nprint clock() == clock();\nprint a + a;",
+			[]string{
+				"2: warning: Comparison of 'a' with itself. [self-comparison]",
+				"3: warning: Comparison of '(+ a 1.0)' with itself. [self-comparison]",
+			},
+		},
+		{
+			"constant condition",
+			"if (true) print 1;\nif ((nil)) print 2;\nwhile (0) print 3;\nfor (;\"s\";) print 4;\nprint false ? 1 : 2;\nwhile (true) {}",
+			[]string{
+				"1: warning: Condition is always true. [constant-condition]",
+				"2: warning: Condition is always false. [constant-condition]",
+				"3: warning: Condition is always true. [constant-condition]",
+				"4: warning: Condition is always true. [constant-condition]",
+				"5: warning: Condition is always false. [constant-condition]",
+			},
+		},
+		{
+			"inconsistent return",
+			"fun a(n) {\n  if (n) return 1;\n  return;\n}\nfun b(n) {\n  if (n) return 1;\n}\nfun c(n) {\n  if (n) return 1; else return 2;\n}\nfun d() { while (true) { return 1; } }\nfun e() { return; }",
+			[]string{
+				"3: warning: Function 'a' returns a value elsewhere but not here. [inconsistent-return]",
+				"5: warning: Function 'b' returns a value on some paths but can reach its end without one. [inconsistent-return]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := check(t, tt.input, Config{})
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestSuppressions(t *testing.T) {
+	input := `fun f(a) {
+  var x = 1; // lint:ignore unused-variable
+  // lint:ignore
+  var y = a == a;
+  var z = 1; // lint:ignore unused-parameter, self-comparison
+}`
+	expected := "5: warning: Variable 'z' is declared but never used. [unused-variable]"
+	if got := strings.Join(check(t, input, Config{}), "\n"); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	input := "fun f(a) { var x = 1; }"
+	cfg := Config{
+		Disabled: map[string]bool{"unused-parameter": true},
+		Severity: map[string]Severity{"unused-variable": Info},
+	}
+	expected := "1: info: Variable 'x' is declared but never used. [unused-variable]"
+	if got := strings.Join(check(t, input, cfg), "\n"); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
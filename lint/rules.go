@@ -0,0 +1,151 @@
+package lint
+
+import "github.com/codecrafters-io/interpreter-starter-go/ast"
+
+// checkSyntax runs the rules that look at one node at a time:
+// unreachable-code, self-comparison, constant-condition and
+// inconsistent-return.
+func checkSyntax(l *linter, program *ast.Program) {
+	checkUnreachable(l, program.Statements)
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.BlockStatement:
+			checkUnreachable(l, node.Statements)
+		case *ast.InfixExpression:
+			checkSelfComparison(l, node)
+		case *ast.IfStatement:
+			checkConstantCondition(l, node.Condition, node.Token.Line)
+		case *ast.WhileStatement:
+			if b, ok := unparen(node.Condition).(*ast.Boolean); !ok || !b.Value {
+				checkConstantCondition(l, node.Condition, node.Token.Line) // while (true) is a deliberate loop
+			}
+		case *ast.ForStatement:
+			checkConstantCondition(l, node.Condition, node.Token.Line)
+		case *ast.ConditionalExpression:
+			checkConstantCondition(l, node.Condition, node.Token.Line)
+		case *ast.FunctionLiteral:
+			checkReturns(l, node)
+		}
+		return true
+	})
+}
+
+// checkUnreachable reports the first statement after a return or throw.
+func checkUnreachable(l *linter, statements []ast.Statement) {
+	for i, stmt := range statements[:max(len(statements)-1, 0)] {
+		switch stmt.(type) {
+		case *ast.ReturnStatement, *ast.ThrowStatement:
+			l.report("unreachable-code", lineOf(statements[i+1]), "Unreachable code.")
+			return
+		}
+	}
+}
+
+func lineOf(stmt ast.Statement) int {
+	if block, ok := stmt.(*ast.BlockStatement); ok {
+		return block.Token.Line
+	}
+	return ast.StatementLine(stmt)
+}
+
+var comparisons = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// checkSelfComparison reports comparisons whose operands are the same
+// expression. Operands containing calls are skipped, since a call may
+// return something different each time.
+func checkSelfComparison(l *linter, node *ast.InfixExpression) {
+	left, right := unparen(node.Left), unparen(node.Right)
+	if !comparisons[node.Operator] || left.String() != right.String() || hasCall(left) {
+		return
+	}
+	l.report("self-comparison", node.Token.Line, "Comparison of '%s' with itself.", left.String())
+}
+
+func hasCall(expr ast.Expression) bool {
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if _, ok := node.(*ast.CallExpression); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkConstantCondition reports a condition that is a literal.
+func checkConstantCondition(l *linter, condition ast.Expression, line int) {
+	switch cond := unparen(condition).(type) {
+	case *ast.Boolean:
+		l.report("constant-condition", line, "Condition is always %t.", cond.Value)
+	case *ast.Nil:
+		l.report("constant-condition", line, "Condition is always false.")
+	case *ast.NumberLiteral, *ast.StringLiteral:
+		l.report("constant-condition", line, "Condition is always true.")
+	}
+}
+
+func unparen(expr ast.Expression) ast.Expression {
+	for {
+		group, ok := expr.(*ast.GroupExpression)
+		if !ok {
+			return expr
+		}
+		expr = group.Expression
+	}
+}
+
+// checkReturns reports a function that returns a value on some paths but
+// returns nothing, or falls off its end, on others. `return;` and
+// `return nil;` both count as returning nothing.
+func checkReturns(l *linter, fn *ast.FunctionLiteral) {
+	var valued, bare []*ast.ReturnStatement
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FunctionLiteral:
+			return false // nested functions are checked on their own
+		case *ast.ReturnStatement:
+			if _, ok := node.ReturnValue.(*ast.Nil); ok {
+				bare = append(bare, node)
+			} else {
+				valued = append(valued, node)
+			}
+		}
+		return true
+	})
+
+	if len(valued) == 0 {
+		return
+	}
+	if len(bare) > 0 {
+		l.report("inconsistent-return", bare[0].Token.Line, "Function '%s' returns a value elsewhere but not here.", fn.Name.Value)
+	} else if !terminates(fn.Body) {
+		l.report("inconsistent-return", fn.Token.Line, "Function '%s' returns a value on some paths but can reach its end without one.", fn.Name.Value)
+	}
+}
+
+// terminates reports whether control can never run past the end of stmt.
+func terminates(stmt ast.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStatement, *ast.ThrowStatement:
+		return true
+	case *ast.BlockStatement:
+		for _, s := range stmt.Statements {
+			if terminates(s) {
+				return true
+			}
+		}
+	case *ast.IfStatement:
+		return stmt.Alternative != nil && terminates(stmt.Consequence) && terminates(stmt.Alternative)
+	case *ast.WhileStatement: // Lox has no break, so only the condition ends a loop
+		b, ok := unparen(stmt.Condition).(*ast.Boolean)
+		return ok && b.Value
+	case *ast.ForStatement:
+		return stmt.Condition == nil
+	case *ast.TryStatement:
+		if stmt.Finally != nil && terminates(stmt.Finally) {
+			return true
+		}
+		return terminates(stmt.Block) && (stmt.Catch == nil || terminates(stmt.Catch))
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/interpreter-starter-go/ast"
+)
+
+// The resolver walks the program with a stack of lexical scopes, as the
+// evaluator's environments would be at run time, and implements the rules
+// that depend on where names are declared: unused-variable,
+// unused-parameter, shadowed-variable and undeclared-assignment.
+
+type kind int
+
+const (
+	localVariable kind = iota
+	localFunction
+	parameter
+	binding // imports and catch parameters, which are never reported unused
+)
+
+type variable struct {
+	name string
+	line int
+	kind kind
+	used bool
+}
+
+type scope struct {
+	vars  map[string]*variable
+	order []*variable
+}
+
+type resolver struct {
+	l      *linter
+	scopes []*scope // the global scope is first
+}
+
+func newResolver(l *linter) *resolver {
+	return &resolver{l: l}
+}
+
+func (r *resolver) program(program *ast.Program) {
+	r.push()
+	// Functions may refer to globals declared after them, so every
+	// top-level name is known before any code is resolved.
+	for _, stmt := range program.Statements {
+		switch stmt := stmt.(type) {
+		case *ast.VarStatement:
+			r.declare(stmt.Name, localVariable)
+		case *ast.ExpressionStatement:
+			if fn, ok := stmt.Expression.(*ast.FunctionLiteral); ok {
+				r.declare(fn.Name, localFunction)
+			}
+		case *ast.ImportStatement:
+			r.statement(stmt)
+		}
+	}
+	for _, stmt := range program.Statements {
+		r.statement(stmt)
+	}
+	// Globals may be used by modules importing this file, so they are
+	// never reported unused.
+	r.scopes = r.scopes[:0]
+}
+
+func (r *resolver) push() {
+	r.scopes = append(r.scopes, &scope{vars: map[string]*variable{}})
+}
+
+// pop closes the innermost scope and reports what it declared but never read.
+func (r *resolver) pop() {
+	s := r.scopes[len(r.scopes)-1]
+	r.scopes = r.scopes[:len(r.scopes)-1]
+
+	for _, v := range s.order {
+		if v.used || strings.HasPrefix(v.name, "_") {
+			continue
+		}
+		switch v.kind {
+		case localVariable:
+			r.l.report("unused-variable", v.line, "Variable '%s' is declared but never used.", v.name)
+		case localFunction:
+			r.l.report("unused-variable", v.line, "Function '%s' is declared but never used.", v.name)
+		case parameter:
+			r.l.report("unused-parameter", v.line, "Parameter '%s' is never used.", v.name)
+		}
+	}
+}
+
+// declare adds name to the innermost scope. Redeclaring a name in the same
+// scope replaces it; declaring one that an enclosing scope already has
+// shadows it.
+func (r *resolver) declare(name *ast.Identifier, k kind) {
+	if name == nil {
+		return
+	}
+	current := r.scopes[len(r.scopes)-1]
+	if existing, ok := current.vars[name.Value]; ok && len(r.scopes) == 1 {
+		existing.line = name.Token.Line
+		return
+	}
+	if outer := r.lookup(name.Value, len(r.scopes)-1); outer != nil && k != binding {
+		r.l.report("shadowed-variable", name.Token.Line, "'%s' shadows the variable declared on line %d.", name.Value, outer.line)
+	}
+
+	v := &variable{name: name.Value, line: name.Token.Line, kind: k}
+	current.vars[name.Value] = v
+	current.order = append(current.order, v)
+}
+
+// lookup finds name in the scopes below depth, innermost first.
+func (r *resolver) lookup(name string, depth int) *variable {
+	for i := depth - 1; i >= 0; i-- {
+		if v, ok := r.scopes[i].vars[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// use marks a read of name. Names that resolve to nothing are builtins or
+// runtime errors; neither is the linter's concern.
+func (r *resolver) use(name string) {
+	if v := r.lookup(name, len(r.scopes)); v != nil {
+		v.used = true
+	}
+}
+
+// assign checks a write to name. Compound assignments and ++/-- also read
+// the variable.
+func (r *resolver) assign(name *ast.Identifier, reads bool) {
+	v := r.lookup(name.Value, len(r.scopes))
+	if v == nil {
+		r.l.report("undeclared-assignment", name.Token.Line, "Assignment to undeclared variable '%s'.", name.Value)
+		return
+	}
+	if reads {
+		v.used = true
+	}
+}
+
+func (r *resolver) block(statements []ast.Statement) {
+	r.push()
+	for _, stmt := range statements {
+		r.statement(stmt)
+	}
+	r.pop()
+}
+
+func (r *resolver) statement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.VarStatement:
+		r.expression(stmt.Value)
+		r.declare(stmt.Name, localVariable)
+	case *ast.BlockStatement:
+		r.block(stmt.Statements)
+	case *ast.ExpressionStatement:
+		r.expression(stmt.Expression)
+	case *ast.IfStatement:
+		r.expression(stmt.Condition)
+		r.statement(stmt.Consequence)
+		r.statement(stmt.Alternative)
+	case *ast.WhileStatement:
+		r.expression(stmt.Condition)
+		r.statement(stmt.Consequence)
+	case *ast.ForStatement:
+		r.push()
+		r.statement(stmt.Init)
+		r.expression(stmt.Condition)
+		r.statement(stmt.Increment)
+		r.statement(stmt.Body)
+		r.pop()
+	case *ast.ReturnStatement:
+		r.expression(stmt.ReturnValue)
+	case *ast.ThrowStatement:
+		r.expression(stmt.Value)
+	case *ast.TryStatement:
+		r.statement(stmt.Block)
+		if stmt.Catch != nil {
+			r.push()
+			r.declare(stmt.CatchParam, binding)
+			r.statement(stmt.Catch)
+			r.pop()
+		}
+		if stmt.Finally != nil {
+			r.statement(stmt.Finally)
+		}
+	case *ast.ImportStatement:
+		r.declare(stmt.Alias, binding)
+		for _, name := range stmt.Names {
+			r.declare(name, binding)
+		}
+	case *ast.TestStatement:
+		r.statement(stmt.Body)
+	}
+}
+
+func (r *resolver) function(fn *ast.FunctionLiteral) {
+	if len(r.scopes) > 1 {
+		r.declare(fn.Name, localFunction)
+	}
+
+	// The body is a block nested inside the parameters' scope, as it is at
+	// run time, so a local may shadow a parameter.
+	r.push()
+	for _, param := range fn.Parameters {
+		r.declare(param, parameter)
+	}
+	r.block(fn.Body.Statements)
+	r.pop()
+}
+
+func (r *resolver) expression(expr ast.Expression) {
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Identifier:
+			r.use(node.Value)
+		case *ast.FunctionLiteral:
+			r.function(node)
+			return false
+		case *ast.AssignExpression:
+			r.expression(node.Value)
+			r.assign(node.Name, false)
+			return false
+		case *ast.CompoundAssignExpression:
+			r.target(node.Target)
+			r.expression(node.Value)
+			return false
+		case *ast.UpdateExpression:
+			r.target(node.Target)
+			return false
+		case *ast.GetExpression:
+			r.expression(node.Object) // the name is a property, not a variable
+			return false
+		}
+		return true
+	})
+}
+
+// target resolves the operand of a compound assignment or ++/--.
+func (r *resolver) target(expr ast.Expression) {
+	if id, ok := expr.(*ast.Identifier); ok {
+		r.assign(id, true)
+		return
+	}
+	r.expression(expr)
+}
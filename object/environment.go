@@ -36,6 +36,18 @@ func (e *Environment) Assign(name string, obj Object) Object {
 	return obj
 }
 
+// Clone returns a copy of the current environment's bindings that shares
+// the same outer environment. It is used to give each loop iteration its
+// own set of loop variables, so closures created in one iteration do not
+// observe updates made by later ones.
+func (e *Environment) Clone() *Environment {
+	env := NewEnclosedEnvironment(e.outer)
+	for name, obj := range e.store {
+		env.store[name] = obj
+	}
+	return env
+}
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
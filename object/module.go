@@ -0,0 +1,26 @@
+package object
+
+import "strings"
+
+// Module is a source file that has been evaluated into its own environment.
+// Only names that do not start with an underscore are visible to importers.
+type Module struct {
+	Name string
+	Path string
+	Env  *Environment
+}
+
+func (m *Module) Type() ObjectType { return MODULE_OBJ }
+func (m *Module) Inspect() string  { return "<module " + m.Name + ">" }
+
+func (m *Module) GetProperty(name string) (Object, bool) {
+	if !IsExported(name) {
+		return nil, false
+	}
+	return m.Env.Get(name)
+}
+
+// IsExported reports whether a top-level module name can be imported.
+func IsExported(name string) bool {
+	return !strings.HasPrefix(name, "_")
+}
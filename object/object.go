@@ -3,6 +3,7 @@ package object
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/codecrafters-io/interpreter-starter-go/ast"
 )
@@ -20,6 +21,12 @@ const (
 	NATIVE_FUNCTION_OBJ            = "NATIVE_FUNCTION"
 	FUNCTION_OBJ                   = "FUNCTION"
 	RETURN_VALUE_OBJ               = "RETURN_VALUE"
+	ERROR_VALUE_OBJ                = "ERROR_VALUE"
+	MODULE_OBJ                     = "MODULE"
+	LIST_OBJ                       = "LIST"
+	EXIT_OBJ                       = "EXIT"
+	MAP_OBJ                        = "MAP"
+	REGEX_OBJ                      = "REGEX"
 )
 
 type Object interface {
@@ -27,6 +34,12 @@ type Object interface {
 	Inspect() string
 }
 
+// PropertyGetter is implemented by objects that expose named properties
+// through the `object.name` syntax.
+type PropertyGetter interface {
+	GetProperty(name string) (Object, bool)
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -53,11 +66,88 @@ type Number struct {
 func (n *Number) Type() ObjectType { return NUMBER_OBJ }
 func (n *Number) Inspect() string  { return fmt.Sprintf("%g", n.Value) }
 
-type Error struct{ Message string }
+// Error is a runtime error that is unwinding the stack. Line and Stack are
+// filled in by the evaluator the first time the error leaves a statement.
+type Error struct {
+	Message string
+	Line    int
+	Stack   string
+	Value   Object // the thrown value, nil for errors raised by the interpreter
+}
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
 func (e *Error) Inspect() string  { return e.Message }
 
+// ErrorValue is an Error that has been caught by a catch clause and is now
+// an ordinary value that can be stored, inspected and thrown again.
+type ErrorValue struct {
+	Error *Error
+}
+
+func (ev *ErrorValue) Type() ObjectType { return ERROR_VALUE_OBJ }
+func (ev *ErrorValue) Inspect() string  { return ev.Error.Message }
+func (ev *ErrorValue) GetProperty(name string) (Object, bool) {
+	switch name {
+	case "message":
+		return &String{Value: ev.Error.Message}, true
+	case "line":
+		return &Number{Value: float64(ev.Error.Line)}, true
+	case "stack":
+		return &String{Value: ev.Error.Stack}, true
+	case "value":
+		if ev.Error.Value != nil {
+			return ev.Error.Value, true
+		}
+		return &String{Value: ev.Error.Message}, true
+	}
+	return nil, false
+}
+
+type List struct {
+	Elements []Object
+}
+
+func (l *List) Type() ObjectType { return LIST_OBJ }
+func (l *List) Inspect() string {
+	elements := []string{}
+	for _, el := range l.Elements {
+		elements = append(elements, el.Inspect())
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Map is a string-keyed map that remembers insertion order, so printing a
+// map or listing its keys is deterministic.
+type Map struct {
+	Keys  []string
+	Pairs map[string]Object
+}
+
+func NewMap() *Map {
+	return &Map{Pairs: make(map[string]Object)}
+}
+
+func (m *Map) Get(key string) (Object, bool) {
+	value, ok := m.Pairs[key]
+	return value, ok
+}
+
+func (m *Map) Set(key string, value Object) {
+	if _, ok := m.Pairs[key]; !ok {
+		m.Keys = append(m.Keys, key)
+	}
+	m.Pairs[key] = value
+}
+
+func (m *Map) Type() ObjectType { return MAP_OBJ }
+func (m *Map) Inspect() string {
+	pairs := []string{}
+	for _, key := range m.Keys {
+		pairs = append(pairs, key+": "+m.Pairs[key].Inspect())
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
 type Print struct {
 	Value Object
 }
@@ -66,6 +156,8 @@ func (p *Print) Type() ObjectType { return PRINT_OBJ }
 func (p *Print) Inspect() string  { return "" }
 
 type Function struct {
+	Name       string
+	Line       int // line of the `fun` keyword
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
@@ -75,11 +167,22 @@ func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
 func (f *Function) Inspect() string {
 	var out bytes.Buffer
 
-	out.WriteString("<fn foo>")
+	out.WriteString("<fn ")
+	out.WriteString(f.Name)
+	out.WriteString(">")
 
 	return out.String()
 }
 
+// Exit is returned by the exit native. Like an Error it unwinds every
+// enclosing statement, but it cannot be caught and carries a status code.
+type Exit struct {
+	Code int
+}
+
+func (ex *Exit) Type() ObjectType { return EXIT_OBJ }
+func (ex *Exit) Inspect() string  { return fmt.Sprintf("exit(%d)", ex.Code) }
+
 type ReturnValue struct {
 	Value Object
 }
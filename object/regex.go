@@ -0,0 +1,17 @@
+package object
+
+import "regexp"
+
+type Regex struct {
+	Regexp *regexp.Regexp
+}
+
+func (r *Regex) Type() ObjectType { return REGEX_OBJ }
+func (r *Regex) Inspect() string  { return "/" + r.Regexp.String() + "/" }
+
+func (r *Regex) GetProperty(name string) (Object, bool) {
+	if name == "pattern" {
+		return &String{Value: r.Regexp.String()}, true
+	}
+	return nil, false
+}
@@ -14,35 +14,73 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // = or +=
+	CONDITIONAL // c ? a : b
+	COALESCE    // a ?? b or a ?: b
 	AND         // &&
 	OR          // ||
 	LESSGREATER // > or <
 	EQUALS      // ==
+	BIT_OR      // |
+	BIT_XOR     // ^
+	BIT_AND     // &
+	SHIFT       // << or >>
 	SUM         // +
-	PRODUCT     // *
+	PRODUCT     // *, / or %
 	PREFIX      // -X or !X
+	POWER       // **
 	CALL        // myFunction(X)
 	INDEX       // array[index]
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQUAL_EQUAL:   EQUALS,
-	token.BANG_EQUAL:    EQUALS,
-	token.LESS:          LESSGREATER,
-	token.GREATER:       LESSGREATER,
-	token.LESS_EQUAL:    LESSGREATER,
-	token.GREATER_EQUAL: LESSGREATER,
-	token.AND:           AND,
-	token.OR:            OR,
-	token.PLUS:          SUM,
-	token.MINUS:         SUM,
-	token.SLASH:         PRODUCT,
-	token.STAR:          PRODUCT,
-	token.LEFT_PAREN:    CALL,
-	token.LEFT_BRACE:    INDEX,
+	token.EQUAL:             ASSIGN,
+	token.PLUS_EQUAL:        ASSIGN,
+	token.MINUS_EQUAL:       ASSIGN,
+	token.STAR_EQUAL:        ASSIGN,
+	token.SLASH_EQUAL:       ASSIGN,
+	token.PERCENT_EQUAL:     ASSIGN,
+	token.PLUS_PLUS:         CALL,
+	token.MINUS_MINUS:       CALL,
+	token.QUESTION:          CONDITIONAL,
+	token.QUESTION_QUESTION: COALESCE,
+	token.QUESTION_COLON:    COALESCE,
+	token.EQUAL_EQUAL:       EQUALS,
+	token.BANG_EQUAL:        EQUALS,
+	token.LESS:              LESSGREATER,
+	token.GREATER:           LESSGREATER,
+	token.LESS_EQUAL:        LESSGREATER,
+	token.GREATER_EQUAL:     LESSGREATER,
+	token.AND:               AND,
+	token.OR:                OR,
+	token.PLUS:              SUM,
+	token.MINUS:             SUM,
+	token.SLASH:             PRODUCT,
+	token.STAR:              PRODUCT,
+	token.PERCENT:           PRODUCT,
+	token.STAR_STAR:         POWER,
+	token.PIPE:              BIT_OR,
+	token.CARET:             BIT_XOR,
+	token.AMPERSAND:         BIT_AND,
+	token.LESS_LESS:         SHIFT,
+	token.GREATER_GREATER:   SHIFT,
+	token.LEFT_PAREN:        CALL,
+	token.DOT:               CALL,
+	token.LEFT_BRACKET:      INDEX,
+}
+
+// divOperator is the contextual keyword for integer division. It stays an
+// identifier so that the div() native can still be called.
+const divOperator = "div"
+
+func isDivOperator(tok token.Token) bool {
+	return tok.Type == token.IDENTIFIER && tok.Lexeme == divOperator
 }
 
 func (p *Parser) curPrecedence() int {
+	if isDivOperator(p.curToken) {
+		return PRODUCT
+	}
 	if p, ok := precedences[p.curToken.Type]; ok {
 		return p
 	}
@@ -74,6 +112,9 @@ func (p *Parser) peekTokenIs(t token.TokenType) bool {
 }
 
 func (p *Parser) peekPrecedence() int {
+	if isDivOperator(p.peekToken) {
+		return PRODUCT
+	}
 	if p, ok := precedences[p.peekToken.Type]; ok {
 		return p
 	}
@@ -153,17 +194,31 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.NIL, p.parseNil)
 	p.registerPrefix(token.NUMBER, p.parseNumberLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.INTERPOLATION, p.parseInterpolatedString)
+	p.registerPrefix(token.INVALID_ESCAPE, p.parseInvalidEscape)
+	p.registerPrefix(token.UNTERMINATED_COMMENT, p.parseUnterminatedComment)
 	p.registerPrefix(token.LEFT_PAREN, p.parseGroupExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
+	p.registerPrefix(token.TILDE, p.parsePrefixExpression)
+	p.registerPrefix(token.PLUS_PLUS, p.parsePrefixUpdateExpression)
+	p.registerPrefix(token.MINUS_MINUS, p.parsePrefixUpdateExpression)
 	p.registerPrefix(token.PRINT, p.parsePrintStatement)
 	p.registerPrefix(token.IDENTIFIER, p.parseIdentifier)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.LEFT_BRACKET, p.parseListLiteral)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.STAR, p.parseInfixExpression)
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)
+	p.registerInfix(token.STAR_STAR, p.parsePowerExpression)
+	p.registerInfix(token.PIPE, p.parseInfixExpression)
+	p.registerInfix(token.CARET, p.parseInfixExpression)
+	p.registerInfix(token.AMPERSAND, p.parseInfixExpression)
+	p.registerInfix(token.LESS_LESS, p.parseInfixExpression)
+	p.registerInfix(token.GREATER_GREATER, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.LESS, p.parseInfixExpression)
 	p.registerInfix(token.GREATER, p.parseInfixExpression)
@@ -174,6 +229,19 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.OR, p.parseInfixExpression)
 	p.registerInfix(token.AND, p.parseInfixExpression)
 	p.registerInfix(token.LEFT_PAREN, p.parseCallExpression)
+	p.registerInfix(token.DOT, p.parseGetExpression)
+	p.registerInfix(token.LEFT_BRACKET, p.parseIndexExpression)
+	p.registerInfix(token.EQUAL, p.parseIndexAssignExpression)
+	p.registerInfix(token.PLUS_EQUAL, p.parseCompoundAssignExpression)
+	p.registerInfix(token.MINUS_EQUAL, p.parseCompoundAssignExpression)
+	p.registerInfix(token.STAR_EQUAL, p.parseCompoundAssignExpression)
+	p.registerInfix(token.SLASH_EQUAL, p.parseCompoundAssignExpression)
+	p.registerInfix(token.PERCENT_EQUAL, p.parseCompoundAssignExpression)
+	p.registerInfix(token.PLUS_PLUS, p.parsePostfixUpdateExpression)
+	p.registerInfix(token.QUESTION, p.parseConditionalExpression)
+	p.registerInfix(token.QUESTION_QUESTION, p.parseCoalesceExpression)
+	p.registerInfix(token.QUESTION_COLON, p.parseCoalesceExpression)
+	p.registerInfix(token.MINUS_MINUS, p.parsePostfixUpdateExpression)
 
 	// Read two tokens, so curToken and peekToken are both set
 	// Sets the peekToken by calling the lexer's NextToken method
@@ -202,11 +270,40 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseForStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.TRY:
+		return p.parseTryStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.IDENTIFIER:
+		if p.curToken.Lexeme == "test" && p.peekTokenIs(token.STRING) {
+			return p.parseTestStatement()
+		}
+		return p.parseExpressmentStatement()
 	default:
 		return p.parseExpressmentStatement()
 	}
 }
 
+// parseTestStatement parses `test "name" { ... }`. `test` is a contextual
+// keyword, so it remains usable as an ordinary identifier.
+func (p *Parser) parseTestStatement() ast.Statement {
+	stmt := &ast.TestStatement{Token: p.curToken}
+
+	p.nextToken()
+	stmt.Name = p.curToken.Literal
+	if !p.expectPeek(token.LEFT_BRACE) {
+		p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect '{' after test name.", p.curToken.Line))
+		return nil
+	}
+	stmt.Body = p.parseBlockStatement()
+	if stmt.Body == nil {
+		return nil
+	}
+	return stmt
+}
+
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.curToken}
 
@@ -223,6 +320,153 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+
+	p.nextToken()
+	if p.curTokenIs(token.SEMICOLON) {
+		p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect expression after 'throw'.", stmt.Token.Line))
+		return nil
+	}
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+func (p *Parser) parseTryStatement() *ast.TryStatement {
+	stmt := &ast.TryStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.LEFT_BRACE) {
+		p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect '{' after 'try'.", p.curToken.Line))
+		return nil
+	}
+	stmt.Block = p.parseBlockStatement()
+	if stmt.Block == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+
+		if p.peekTokenIs(token.LEFT_PAREN) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENTIFIER) {
+				p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect catch variable name.", p.curToken.Line))
+				return nil
+			}
+			stmt.CatchParam = &ast.Identifier{Token: p.curToken, Value: p.curToken.Lexeme}
+			if !p.expectPeek(token.RIGHT_PAREN) {
+				p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect ')' after catch variable.", p.curToken.Line))
+				return nil
+			}
+		}
+
+		if !p.expectPeek(token.LEFT_BRACE) {
+			p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect '{' after 'catch'.", p.curToken.Line))
+			return nil
+		}
+		stmt.Catch = p.parseBlockStatement()
+		if stmt.Catch == nil {
+			return nil
+		}
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+		if !p.expectPeek(token.LEFT_BRACE) {
+			p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect '{' after 'finally'.", p.curToken.Line))
+			return nil
+		}
+		stmt.Finally = p.parseBlockStatement()
+		if stmt.Finally == nil {
+			return nil
+		}
+	}
+
+	if stmt.Catch == nil && stmt.Finally == nil {
+		p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect 'catch' or 'finally' after try block.", stmt.Token.Line))
+		return nil
+	}
+
+	return stmt
+}
+
+// parseImportStatement parses both import forms. `as` and `from` are
+// contextual words rather than keywords, so they stay usable as identifiers.
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+
+	if p.peekTokenIs(token.LEFT_BRACE) {
+		p.nextToken()
+		for {
+			if !p.expectPeek(token.IDENTIFIER) {
+				p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect name in import list.", p.curToken.Line))
+				return nil
+			}
+			stmt.Names = append(stmt.Names, &ast.Identifier{Token: p.curToken, Value: p.curToken.Lexeme})
+			if !p.peekTokenIs(token.COMMA) {
+				break
+			}
+			p.nextToken()
+		}
+		if !p.expectPeek(token.RIGHT_BRACE) {
+			p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect '}' after import list.", p.curToken.Line))
+			return nil
+		}
+		if !p.expectContextual("from") {
+			p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect 'from' after import list.", p.curToken.Line))
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.STRING) {
+		p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect module path.", p.curToken.Line))
+		return nil
+	}
+	stmt.Path = p.curToken.Literal
+
+	if stmt.Names == nil {
+		if !p.expectContextual("as") {
+			p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect 'as' after module path.", p.curToken.Line))
+			return nil
+		}
+		if !p.expectPeek(token.IDENTIFIER) {
+			p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect module name after 'as'.", p.curToken.Line))
+			return nil
+		}
+		stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Lexeme}
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// expectContextual advances past an identifier spelled exactly as word.
+func (p *Parser) expectContextual(word string) bool {
+	if p.peekTokenIs(token.IDENTIFIER) && p.peekToken.Lexeme == word {
+		p.nextToken()
+		return true
+	}
+	return false
+}
+
+func (p *Parser) parseGetExpression(object ast.Expression) ast.Expression {
+	exp := &ast.GetExpression{Token: p.curToken, Object: object}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		p.errors = append(p.errors, fmt.Sprintf("[line %d] Error at '%s': Expect property name after '.'.", p.peekToken.Line, p.peekToken.Lexeme))
+		return nil
+	}
+	exp.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Lexeme}
+
+	return exp
+}
+
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
 	exp.Arguments = p.parseExpressionList(token.RIGHT_PAREN)
@@ -232,6 +476,28 @@ func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseListLiteral() ast.Expression {
+	list := &ast.ListLiteral{Token: p.curToken}
+	list.Elements = p.parseExpressionList(token.RIGHT_BRACKET)
+	if !p.curTokenIs(token.RIGHT_BRACKET) {
+		p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect ']' after list elements.", p.curToken.Line))
+		return nil
+	}
+	return list
+}
+
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+
+	p.nextToken()
+	exp.Index = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RIGHT_BRACKET) {
+		p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect ']' after index.", p.curToken.Line))
+		return nil
+	}
+	return exp
+}
+
 func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	list := []ast.Expression{}
 
@@ -402,6 +668,9 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 
 	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
+		if isDivOperator(p.peekToken) {
+			infix = p.parseInfixExpression
+		}
 		if infix == nil {
 			return leftExp
 		}
@@ -435,6 +704,55 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	expr := &ast.InterpolatedString{Token: p.curToken}
+
+	for p.curTokenIs(token.INTERPOLATION) {
+		if p.curToken.Literal != "" {
+			expr.Parts = append(expr.Parts, &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal})
+		}
+
+		p.nextToken()
+		if p.isStringResume(p.curToken) {
+			p.noPrefixParseFnError(token.New(token.RIGHT_BRACE, "}", "null", p.curToken.Line))
+			return nil
+		}
+		part := p.parseExpression(LOWEST)
+		if part == nil {
+			return nil
+		}
+		expr.Parts = append(expr.Parts, part)
+
+		p.nextToken()
+		if !p.isStringResume(p.curToken) {
+			p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect '}' after interpolated expression.", p.curToken.Line))
+			return nil
+		}
+	}
+
+	if p.curToken.Literal != "" {
+		expr.Parts = append(expr.Parts, &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal})
+	}
+	return expr
+}
+
+// isStringResume reports whether tok continues a string after the `}` that
+// closes an interpolated expression.
+func (p *Parser) isStringResume(tok token.Token) bool {
+	return (tok.Type == token.STRING || tok.Type == token.INTERPOLATION) && strings.HasPrefix(tok.Lexeme, "}")
+}
+
+func (p *Parser) parseInvalidEscape() ast.Expression {
+	msg := fmt.Sprintf("[line %d] Error: Invalid escape sequence: %s", p.curToken.Line, p.curToken.Lexeme)
+	p.errors = append(p.errors, msg)
+	return nil
+}
+
+func (p *Parser) parseUnterminatedComment() ast.Expression {
+	p.errors = append(p.errors, fmt.Sprintf("[line %d] Error: Unterminated comment.", p.curToken.Line))
+	return nil
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
 
@@ -499,8 +817,55 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+// parsePowerExpression parses the right operand one level lower so that
+// `**` groups to the right: 2 ** 3 ** 2 is 2 ** (3 ** 2).
+func (p *Parser) parsePowerExpression(left ast.Expression) ast.Expression {
+	expression := &ast.InfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Lexeme,
+		Left:     left,
+	}
+	p.nextToken()
+
+	expression.Right = p.parseExpression(POWER - 1)
+	return expression
+}
+
+// parseConditionalExpression parses `cond ? a : b`. The else branch is
+// parsed one level lower so that conditionals chain to the right.
+func (p *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	expression := &ast.ConditionalExpression{Token: p.curToken, Condition: condition}
+
+	p.nextToken()
+	expression.Consequence = p.parseExpression(LOWEST)
+	if expression.Consequence == nil {
+		return nil
+	}
+	if !p.expectPeek(token.COLON) {
+		p.errors = append(p.errors, fmt.Sprintf("[line %d] Expect ':' after then branch of conditional expression.", p.peekToken.Line))
+		return nil
+	}
+
+	p.nextToken()
+	expression.Alternative = p.parseExpression(CONDITIONAL - 1)
+	return expression
+}
+
+// parseCoalesceExpression parses `??` and `?:`, which group to the right.
+func (p *Parser) parseCoalesceExpression(left ast.Expression) ast.Expression {
+	expression := &ast.InfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Lexeme,
+		Left:     left,
+	}
+	p.nextToken()
+
+	expression.Right = p.parseExpression(COALESCE - 1)
+	return expression
+}
+
 func (p *Parser) parseVarStatement() *ast.VarStatement {
-	stmt := &ast.VarStatement{Token: p.curToken}
+	stmt := &ast.VarStatement{Token: p.curToken, Doc: p.curToken.Doc}
 
 	if !p.expectPeek(token.IDENTIFIER) {
 		return nil
@@ -546,14 +911,80 @@ func (p *Parser) parseAssignExpression(left ast.Expression) ast.Expression {
 		Name:  left.(*ast.Identifier),
 	}
 
-	precedence := p.curPrecedence()
 	p.nextToken()
-	expression.Value = p.parseExpression(precedence)
+	expression.Value = p.parseExpression(LOWEST)
 	return expression
 }
 
+// parseIndexAssignExpression handles `=` after anything but a bare
+// identifier, which parseIdentifier already turns into an AssignExpression.
+func (p *Parser) parseIndexAssignExpression(left ast.Expression) ast.Expression {
+	target, ok := left.(*ast.IndexExpression)
+	if !ok {
+		p.invalidAssignmentTarget(p.curToken)
+		return nil
+	}
+	expression := &ast.IndexAssignExpression{Token: p.curToken, Target: target}
+
+	p.nextToken()
+	expression.Value = p.parseExpression(LOWEST)
+	return expression
+}
+
+func (p *Parser) parseCompoundAssignExpression(left ast.Expression) ast.Expression {
+	if !isAssignable(left) {
+		p.invalidAssignmentTarget(p.curToken)
+		return nil
+	}
+	expression := &ast.CompoundAssignExpression{
+		Token:    p.curToken,
+		Target:   left,
+		Operator: strings.TrimSuffix(p.curToken.Lexeme, "="),
+	}
+
+	p.nextToken()
+	expression.Value = p.parseExpression(LOWEST)
+	return expression
+}
+
+func (p *Parser) parsePrefixUpdateExpression() ast.Expression {
+	expression := &ast.UpdateExpression{Token: p.curToken, Operator: p.curToken.Lexeme, Prefix: true}
+
+	p.nextToken()
+	expression.Target = p.parseExpression(PREFIX)
+	if expression.Target == nil {
+		return nil
+	}
+	if !isAssignable(expression.Target) {
+		p.invalidAssignmentTarget(expression.Token)
+		return nil
+	}
+	return expression
+}
+
+func (p *Parser) parsePostfixUpdateExpression(left ast.Expression) ast.Expression {
+	if !isAssignable(left) {
+		p.invalidAssignmentTarget(p.curToken)
+		return nil
+	}
+	return &ast.UpdateExpression{Token: p.curToken, Operator: p.curToken.Lexeme, Target: left}
+}
+
+func isAssignable(exp ast.Expression) bool {
+	switch exp.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+		return true
+	}
+	return false
+}
+
+func (p *Parser) invalidAssignmentTarget(tok token.Token) {
+	msg := fmt.Sprintf("[line %d] Error at '%s': Invalid assignment target.", tok.Line, tok.Lexeme)
+	p.errors = append(p.errors, msg)
+}
+
 func (p *Parser) parseFunctionLiteral() ast.Expression {
-	fn := &ast.FunctionLiteral{Token: p.curToken}
+	fn := &ast.FunctionLiteral{Token: p.curToken, Doc: p.curToken.Doc}
 
 	if p.peekTokenIs(token.IDENTIFIER) {
 		p.nextToken()
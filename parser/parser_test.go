@@ -290,6 +290,13 @@ func TestInfixExpression(t *testing.T) {
 		{"1 + 2", "(+ 1.0 2.0)"},
 		{"1 - 2", "(- 1.0 2.0)"},
 		{"16 * 38 / 58", "(/ (* 16.0 38.0) 58.0)"},
+		{"7 % 3 * 2", "(* (% 7.0 3.0) 2.0)"},
+		{"7 div 2 + 1", "(+ (div 7.0 2.0) 1.0)"},
+		{"2 ** 3 ** 2", "(** 2.0 (** 3.0 2.0))"},
+		{"-2 ** 2 * 3", "(* (- (** 2.0 2.0)) 3.0)"},
+		{"1 | 2 ^ 3 & 4", "(| 1.0 (^ 2.0 (& 3.0 4.0)))"},
+		{"1 << 2 + 3 == 32", "(== (<< 1.0 (+ 2.0 3.0)) 32.0)"},
+		{"x & 1 == 0", "(== (& x 1.0) 0.0)"},
 	}
 
 	for _, tt := range tests {
@@ -796,6 +803,11 @@ func TestSyntaxError(t *testing.T) {
 		{`for (var a = 1; {}; a = a + 1) {}`, "[line 1] Error at '{': Expect expression."},
 		{`for (var a = 1; a < 2; {}) {}`, "[line 1] Empty increment condition."},
 		{`for ({}; a < 2; a = a + 1) {}`, "[line 1] Empty initial condition."},
+		{`print "a\qb";`, `[line 1] Error: Invalid escape sequence: \q`},
+		{`print "${}";`, "[line 1] Error at '}': Expect expression."},
+		{`print "${a b}";`, "[line 1] Expect '}' after interpolated expression."},
+		{`print a ? b;`, "[line 1] Expect ':' after then branch of conditional expression."},
+		{"print 1; /* open", "[line 1] Error: Unterminated comment."},
 	}
 
 	for _, tt := range tests {
@@ -957,3 +969,383 @@ func TestReturnStatement(t *testing.T) {
 
 	testLiteralExpression(t, stmt.ReturnValue, 1)
 }
+
+func TestThrowStatement(t *testing.T) {
+	input := `throw "boom";`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program has not enough statements. got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ThrowStatement. got=%T", program.Statements[0])
+	}
+
+	testStringLiteral(t, stmt.Value, "boom")
+}
+
+func TestTryStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { print 1; } catch (e) { print e; }`, "try {(print 1.0)} catch (e) {(print e)}"},
+		{`try { print 1; } catch { print 2; }`, "try {(print 1.0)} catch {(print 2.0)}"},
+		{`try { print 1; } finally { print 3; }`, "try {(print 1.0)} finally {(print 3.0)}"},
+		{`try {} catch (e) {} finally {}`, "try {} catch (e) {} finally {}"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has not enough statements. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.TryStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.TryStatement. got=%T", program.Statements[0])
+		}
+
+		if stmt.String() != tt.expected {
+			t.Errorf("stmt.String() not %q. got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
+func TestTryStatementErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`try { print 1; }`, "[line 1] Expect 'catch' or 'finally' after try block."},
+		{`try print 1;`, "[line 1] Expect '{' after 'try'."},
+		{`try {} catch (1) {}`, "[line 1] Expect catch variable name."},
+		{`throw;`, "[line 1] Expect expression after 'throw'."},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected error, got none")
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("expected error %q, got %q", tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestGetExpression(t *testing.T) {
+	input := `e.message;`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	get, ok := stmt.Expression.(*ast.GetExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.GetExpression. got=%T", stmt.Expression)
+	}
+
+	testIdentifier(t, get.Object, "e")
+	testIdentifier(t, get.Name, "message")
+}
+
+func TestImportStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "lib/math.lox" as math;`, `import "lib/math.lox" as math;`},
+		{`import { square, cube } from "math.lox";`, `import { square, cube } from "math.lox";`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ImportStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ImportStatement. got=%T", program.Statements[0])
+		}
+
+		if stmt.String() != tt.expected {
+			t.Errorf("stmt.String() not %q. got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
+func TestImportStatementErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`import math;`, "[line 1] Expect module path."},
+		{`import "math.lox";`, "[line 1] Expect 'as' after module path."},
+		{`import { a } "math.lox";`, "[line 1] Expect 'from' after import list."},
+		{`import { } from "math.lox";`, "[line 1] Expect name in import list."},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected error, got none")
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("expected error %q, got %q", tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestListAndIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[]`, "[]"},
+		{`[1, "a", x]`, "[1.0, a, x]"},
+		{`xs[1 + 2]`, "xs[(+ 1.0 2.0)]"},
+		{`f(x)[0][1]`, "f(x)[0.0][1.0]"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		if stmt.Expression.String() != tt.expected {
+			t.Errorf("stmt.Expression.String() not %q. got=%q", tt.expected, stmt.Expression.String())
+		}
+	}
+}
+
+func TestInterpolatedString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a ${x + 1} b"`, `(interpolate "a " (+ x 1.0) " b")`},
+		{`"${x}${y}"`, `(interpolate x y)`},
+		{`"${"in ${x}"}!"`, `(interpolate (interpolate "in " x) "!")`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestCompoundAssignAndUpdateExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`x += 1 + 2`, "x += (+ 1.0 2.0);"},
+		{`x %= 2`, "x %= 2.0;"},
+		{`xs[0] -= 1`, "xs[0.0] -= 1.0;"},
+		{`xs[i] = 3`, "xs[i] = 3.0;"},
+		{`x++ + 1`, "(+ x++ 1.0)"},
+		{`--xs[i]`, "--xs[i]"},
+		{`-x++`, "(- x++)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestInvalidAssignmentTargets(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`1 += 2;`, "[line 1] Error at '+=': Invalid assignment target."},
+		{`a + b += 2;`, "[line 1] Error at '+=': Invalid assignment target."},
+		{`f() = 3;`, "[line 1] Error at '=': Invalid assignment target."},
+		{`++1;`, "[line 1] Error at '++': Invalid assignment target."},
+		{`"s"--;`, "[line 1] Error at '--': Invalid assignment target."},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected error for %q, got none", tt.input)
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("expected error %q, got %q", tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestConditionalExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`a ? b : c`, "(? a b c)"},
+		{`a ? b : c ? d : e`, "(? a b (? c d e))"},
+		{`a ? b ? c : d : e`, "(? a (? b c d) e)"},
+		{`a or b ? 1 + 2 : 3`, "(? (or a b) (+ 1.0 2.0) 3.0)"},
+		{`x = a ? b : c`, "x = (? a b c);"},
+		{`a ?? b ?? c`, "(?? a (?? b c))"},
+		{`a ?: b or c`, "(?: a (or b c))"},
+		{`a ?? b ? c : d`, "(? (?? a b) c d)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestDocCommentsAttachToDeclarations(t *testing.T) {
+	input := `
+/// The answer.
+var answer = 42;
+
+/// Adds two numbers.
+/// Returns their sum.
+fun add(a, b) { return a + b; }
+
+/// Not attached to anything.
+print 1;
+var plain = 1;
+`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 4 {
+		t.Fatalf("program has wrong number of statements. got=%d", len(program.Statements))
+	}
+
+	answer, ok := program.Statements[0].(*ast.VarStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.VarStatement. got=%T", program.Statements[0])
+	}
+	if answer.Doc != "The answer." {
+		t.Errorf("var doc wrong. got=%q", answer.Doc)
+	}
+
+	stmt, ok := program.Statements[1].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[1] is not *ast.ExpressionStatement. got=%T", program.Statements[1])
+	}
+	fn, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.FunctionLiteral. got=%T", stmt.Expression)
+	}
+	if fn.Doc != "Adds two numbers.\nReturns their sum." {
+		t.Errorf("fun doc wrong. got=%q", fn.Doc)
+	}
+
+	plain := program.Statements[3].(*ast.VarStatement)
+	if plain.Doc != "" {
+		t.Errorf("expected no doc on plain var, got=%q", plain.Doc)
+	}
+}
+
+func TestTestStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`test "adds numbers" { print 1; }`, `test "adds numbers" {(print 1.0)}`},
+		{`var test = 1; test = 2;`, `var test = 1.0;test = 2.0;`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestOptionalClausesString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`if (true) print 1;`, `if true (print 1.0)`},
+		{`for (;;) {}`, `for (; ; ) {}`},
+		{`fun f() { return; }`, `fun f () {return nil;}`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package profile
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// WritePprof writes the call stacks as a gzipped profile.proto message, the
+// format read by `go tool pprof`. Each stack becomes a sample valued by its
+// call count and self time. The encoding is done by hand to avoid pulling in
+// a protobuf dependency.
+func (p *Profiler) WritePprof(w io.Writer) error {
+	strs := stringTable{index: map[string]int64{}}
+	strs.add("")
+
+	var msg protoBuffer
+	for _, vt := range [][2]string{{"calls", "count"}, {"time", "nanoseconds"}} {
+		var valueType protoBuffer
+		valueType.varint(1, uint64(strs.add(vt[0])))
+		valueType.varint(2, uint64(strs.add(vt[1])))
+		msg.bytes(1, valueType)
+	}
+
+	ids := map[Func]uint64{}
+	var functions []Func
+	for _, stack := range p.Stacks() {
+		var locations, values protoBuffer
+		for i := len(stack.Frames) - 1; i >= 0; i-- { // leaf first
+			fn := stack.Frames[i]
+			if _, ok := ids[fn]; !ok {
+				ids[fn] = uint64(len(ids) + 1)
+				functions = append(functions, fn)
+			}
+			locations.rawVarint(ids[fn])
+		}
+		values.rawVarint(uint64(stack.Calls))
+		values.rawVarint(uint64(stack.Self.Nanoseconds()))
+
+		var sample protoBuffer
+		sample.bytes(1, locations)
+		sample.bytes(2, values)
+		msg.bytes(2, sample)
+	}
+
+	for _, fn := range functions {
+		var line, location protoBuffer
+		line.varint(1, ids[fn])
+		line.varint(2, uint64(fn.Line))
+		location.varint(1, ids[fn])
+		location.bytes(4, line)
+		msg.bytes(4, location)
+	}
+	for _, fn := range functions {
+		var function protoBuffer
+		function.varint(1, ids[fn])
+		name := fn.Name
+		if fn == (Func{Name: Root}) {
+			name = "script" // pprof drops <...> from names as C++ template arguments
+		}
+		function.varint(2, uint64(strs.add(name)))
+		function.varint(3, uint64(strs.add(name)))
+		function.varint(5, uint64(fn.Line))
+		msg.bytes(5, function)
+	}
+
+	for _, s := range strs.list {
+		msg.bytes(6, protoBuffer(s))
+	}
+	msg.varint(9, uint64(p.started.UnixNano()))
+	msg.varint(10, uint64(p.elapsed.Nanoseconds()))
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(msg); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// stringTable interns the strings that profile.proto messages refer to by
+// index.
+type stringTable struct {
+	list  []string
+	index map[string]int64
+}
+
+func (t *stringTable) add(s string) int64 {
+	if i, ok := t.index[s]; ok {
+		return i
+	}
+	t.index[s] = int64(len(t.list))
+	t.list = append(t.list, s)
+	return t.index[s]
+}
+
+// protoBuffer accumulates protobuf wire-format fields.
+type protoBuffer []byte
+
+func (b *protoBuffer) rawVarint(v uint64) {
+	for v >= 0x80 {
+		*b = append(*b, byte(v)|0x80)
+		v >>= 7
+	}
+	*b = append(*b, byte(v))
+}
+
+// varint writes a varint field, omitting zero values as proto3 does.
+func (b *protoBuffer) varint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	b.rawVarint(uint64(field) << 3)
+	b.rawVarint(v)
+}
+
+// bytes writes a length-delimited field.
+func (b *protoBuffer) bytes(field int, data []byte) {
+	b.rawVarint(uint64(field)<<3 | 2)
+	b.rawVarint(uint64(len(data)))
+	*b = append(*b, data...)
+}
@@ -0,0 +1,144 @@
+// Package profile measures where a Lox program spends its time. The evaluator
+// reports each user function call and return, and the profiler attributes
+// elapsed wall-clock time to functions and to full call stacks.
+package profile
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Root is the name of the frame that stands for top-level script code.
+const Root = "<script>"
+
+// Func identifies a function by name and definition line.
+type Func struct {
+	Name string
+	Line int
+}
+
+func (f Func) String() string {
+	if f.Line == 0 {
+		return f.Name
+	}
+	return f.Name + ":" + strconv.Itoa(f.Line)
+}
+
+// Stats are the measurements of one function. Total time includes callees
+// and counts recursive calls once; self time excludes callees.
+type Stats struct {
+	Func
+	Calls int
+	Total time.Duration
+	Self  time.Duration
+}
+
+// Stack is the self time and call count of one distinct call stack.
+type Stack struct {
+	Frames []Func // outermost first
+	Calls  int
+	Self   time.Duration
+}
+
+// frame is an active call.
+type frame struct {
+	fn       Func
+	start    time.Time
+	children time.Duration // time spent in completed callees
+}
+
+// Profiler records calls between Start and Stop.
+type Profiler struct {
+	// Now is the time source. It defaults to time.Now and can be replaced
+	// to make measurements deterministic.
+	Now func() time.Time
+
+	frames []frame
+	active map[Func]int // number of active calls per function
+	stats  map[Func]*Stats
+	order  []Func // functions in order of first call
+	stacks map[string]*Stack
+
+	started time.Time
+	elapsed time.Duration
+}
+
+// New returns a profiler using the wall clock.
+func New() *Profiler {
+	return &Profiler{
+		Now:    time.Now,
+		active: map[Func]int{},
+		stats:  map[Func]*Stats{},
+		stacks: map[string]*Stack{},
+	}
+}
+
+// Start begins profiling by entering the root frame.
+func (p *Profiler) Start() {
+	p.started = p.Now()
+	p.Enter(Root, 0)
+}
+
+// Stop leaves any frames still active, such as those unwound by exit(),
+// and ends profiling.
+func (p *Profiler) Stop() {
+	for len(p.frames) > 0 {
+		p.Leave()
+	}
+	p.elapsed = p.Now().Sub(p.started)
+}
+
+// Elapsed is the time between Start and Stop.
+func (p *Profiler) Elapsed() time.Duration {
+	return p.elapsed
+}
+
+// Enter records a call to the function name defined on line.
+func (p *Profiler) Enter(name string, line int) {
+	fn := Func{Name: name, Line: line}
+	if _, ok := p.stats[fn]; !ok {
+		p.stats[fn] = &Stats{Func: fn}
+		p.order = append(p.order, fn)
+	}
+	p.stats[fn].Calls++
+	p.active[fn]++
+	p.frames = append(p.frames, frame{fn: fn, start: p.Now()})
+}
+
+// Leave records the return of the innermost active call.
+func (p *Profiler) Leave() {
+	if len(p.frames) == 0 {
+		return
+	}
+	top := p.frames[len(p.frames)-1]
+	elapsed := p.Now().Sub(top.start)
+	self := elapsed - top.children
+
+	stats := p.stats[top.fn]
+	stats.Self += self
+	p.active[top.fn]--
+	if p.active[top.fn] == 0 {
+		stats.Total += elapsed
+	}
+
+	names := make([]string, len(p.frames))
+	frames := make([]Func, len(p.frames))
+	for i, f := range p.frames {
+		names[i] = f.fn.String()
+		frames[i] = f.fn
+	}
+	key := strings.Join(names, ";")
+	stack, ok := p.stacks[key]
+	if !ok {
+		stack = &Stack{Frames: frames}
+		p.stacks[key] = stack
+	}
+	stack.Calls++
+	stack.Self += self
+
+	p.frames = p.frames[:len(p.frames)-1]
+	if len(p.frames) > 0 {
+		p.frames[len(p.frames)-1].children += elapsed
+	}
+}
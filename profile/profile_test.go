@@ -0,0 +1,150 @@
+package profile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock is a time source advanced explicitly by the test.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time           { return c.now }
+func (c *fakeClock) advance(ms time.Duration) { c.now = c.now.Add(ms * time.Millisecond) }
+
+// record runs a fixed call tree: the script calls fib(2), which recurses into
+// fib(1) and fib(0), then calls log once.
+func record() *Profiler {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p := New()
+	p.Now = clock.Now
+
+	p.Start()
+	clock.advance(1)
+	p.Enter("fib", 1)
+	clock.advance(2)
+	p.Enter("fib", 1)
+	clock.advance(3)
+	p.Leave()
+	p.Enter("fib", 1)
+	clock.advance(4)
+	p.Leave()
+	p.Leave()
+	p.Enter("log", 7)
+	clock.advance(5)
+	p.Leave()
+	p.Stop()
+	return p
+}
+
+func TestFunctions(t *testing.T) {
+	p := record()
+	if p.Elapsed() != 15*time.Millisecond {
+		t.Errorf("expected 15ms elapsed, got %s", p.Elapsed())
+	}
+
+	expected := []Stats{
+		{Func: Func{"fib", 1}, Calls: 3, Total: 9 * time.Millisecond, Self: 9 * time.Millisecond},
+		{Func: Func{"log", 7}, Calls: 1, Total: 5 * time.Millisecond, Self: 5 * time.Millisecond},
+		{Func: Func{Root, 0}, Calls: 1, Total: 15 * time.Millisecond, Self: 1 * time.Millisecond},
+	}
+	functions := p.Functions()
+	if len(functions) != len(expected) {
+		t.Fatalf("expected %d functions, got %d", len(expected), len(functions))
+	}
+	for i, want := range expected {
+		if functions[i] != want {
+			t.Errorf("function %d: expected %+v, got %+v", i, want, functions[i])
+		}
+	}
+}
+
+func TestUnwindOnStop(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p := New()
+	p.Now = clock.Now
+
+	p.Start()
+	p.Enter("quit", 3) // exit() unwinds without returning
+	clock.advance(2)
+	p.Stop()
+
+	functions := p.Functions()
+	if len(functions) != 2 || functions[0].Name != "quit" || functions[0].Total != 2*time.Millisecond {
+		t.Errorf("active calls not closed on Stop: %+v", functions)
+	}
+}
+
+func TestWriteTop(t *testing.T) {
+	var out bytes.Buffer
+	if err := record().WriteTop(&out, 2); err != nil {
+		t.Fatalf("WriteTop: %v", err)
+	}
+
+	expected := `total time: 15.000ms
+
+     calls         self   self%        total  total%  function
+         3      9.000ms   60.0%      9.000ms   60.0%  fib:1
+         1      5.000ms   33.3%      5.000ms   33.3%  log:7
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestWriteFolded(t *testing.T) {
+	var out bytes.Buffer
+	if err := record().WriteFolded(&out); err != nil {
+		t.Fatalf("WriteFolded: %v", err)
+	}
+
+	expected := `<script> 1000000
+<script>;fib:1 2000000
+<script>;fib:1;fib:1 7000000
+<script>;log:7 5000000
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestWritePprof(t *testing.T) {
+	var out bytes.Buffer
+	if err := record().WritePprof(&out); err != nil {
+		t.Fatalf("WritePprof: %v", err)
+	}
+
+	zr, err := gzip.NewReader(&out)
+	if err != nil {
+		t.Fatalf("output is not gzipped: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("failed to decompress: %v", err)
+	}
+
+	// Field 1 (sample_type) comes first, as a length-delimited message.
+	if len(data) == 0 || data[0] != 1<<3|2 {
+		t.Fatalf("unexpected leading field: % x", data[:min(len(data), 8)])
+	}
+	for _, name := range []string{"calls", "nanoseconds", "script", "fib", "log"} {
+		if !strings.Contains(string(data), name) {
+			t.Errorf("string table missing %q", name)
+		}
+	}
+}
+
+func TestProtoVarint(t *testing.T) {
+	var b protoBuffer
+	b.rawVarint(300)
+	b.varint(2, 0) // zero values are omitted
+	b.varint(3, 1)
+	if !bytes.Equal(b, []byte{0xac, 0x02, 3 << 3, 1}) {
+		t.Errorf("unexpected encoding: % x", []byte(b))
+	}
+}
@@ -0,0 +1,86 @@
+package profile
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Functions returns the stats of every called function, by descending self
+// time. Ties keep the order in which functions were first called.
+func (p *Profiler) Functions() []Stats {
+	out := make([]Stats, 0, len(p.order))
+	for _, fn := range p.order {
+		out = append(out, *p.stats[fn])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Self > out[j].Self })
+	return out
+}
+
+// Stacks returns every distinct call stack, sorted by its folded name.
+func (p *Profiler) Stacks() []Stack {
+	keys := make([]string, 0, len(p.stacks))
+	for key := range p.stacks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := make([]Stack, len(keys))
+	for i, key := range keys {
+		out[i] = *p.stacks[key]
+	}
+	return out
+}
+
+// WriteTop writes a table of the n functions with the most self time, or all
+// of them when n is not positive.
+func (p *Profiler) WriteTop(w io.Writer, n int) error {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "total time: %s\n\n", formatDuration(p.elapsed))
+	fmt.Fprintf(&out, "%10s %12s %7s %12s %7s  %s\n", "calls", "self", "self%", "total", "total%", "function")
+	functions := p.Functions()
+	if n > 0 && n < len(functions) {
+		functions = functions[:n]
+	}
+	for _, stats := range functions {
+		fmt.Fprintf(&out, "%10d %12s %7s %12s %7s  %s\n",
+			stats.Calls,
+			formatDuration(stats.Self), p.share(stats.Self),
+			formatDuration(stats.Total), p.share(stats.Total),
+			stats.Func)
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func (p *Profiler) share(d time.Duration) string {
+	if p.elapsed <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(d)/float64(p.elapsed))
+}
+
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3fms", float64(d)/float64(time.Millisecond))
+}
+
+// WriteFolded writes one line per call stack in the folded format read by
+// flamegraph.pl and compatible tools: frames joined by semicolons, then the
+// self time in nanoseconds.
+func (p *Profiler) WriteFolded(w io.Writer) error {
+	var out strings.Builder
+	for _, stack := range p.Stacks() {
+		names := make([]string, len(stack.Frames))
+		for i, fn := range stack.Frames {
+			names[i] = fn.String()
+		}
+		fmt.Fprintf(&out, "%s %d\n", strings.Join(names, ";"), stack.Self.Nanoseconds())
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
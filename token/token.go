@@ -7,14 +7,17 @@ type Token struct {
 	Lexeme  string
 	Literal string
 	Line    int
+	Doc     string // text of the `///` comments directly preceding the token
 }
 
 const (
 	EOF = "EOF"
 
 	// Errors
-	ILLEGAL             = "ILLEGAL"
-	UNTERMINATED_STRING = "UNTERMINATED_STRING"
+	ILLEGAL              = "ILLEGAL"
+	UNTERMINATED_STRING  = "UNTERMINATED_STRING"
+	INVALID_ESCAPE       = "INVALID_ESCAPE"
+	UNTERMINATED_COMMENT = "UNTERMINATED_COMMENT"
 
 	// Operators
 	DOT           = "DOT"
@@ -31,19 +34,47 @@ const (
 	GREATER_EQUAL = "GREATER_EQUAL"
 	SLASH         = "SLASH"
 
+	PERCENT         = "PERCENT"
+	STAR_STAR       = "STAR_STAR"
+	AMPERSAND       = "AMPERSAND"
+	PIPE            = "PIPE"
+	CARET           = "CARET"
+	TILDE           = "TILDE"
+	LESS_LESS       = "LESS_LESS"
+	GREATER_GREATER = "GREATER_GREATER"
+
+	PLUS_EQUAL    = "PLUS_EQUAL"
+	MINUS_EQUAL   = "MINUS_EQUAL"
+	STAR_EQUAL    = "STAR_EQUAL"
+	SLASH_EQUAL   = "SLASH_EQUAL"
+	PERCENT_EQUAL = "PERCENT_EQUAL"
+	PLUS_PLUS     = "PLUS_PLUS"
+	MINUS_MINUS   = "MINUS_MINUS"
+
 	// Delimiters
-	LEFT_PAREN  = "LEFT_PAREN"
-	RIGHT_PAREN = "RIGHT_PAREN"
-	LEFT_BRACE  = "LEFT_BRACE"
-	RIGHT_BRACE = "RIGHT_BRACE"
-	SEMICOLON   = "SEMICOLON"
-	COMMA       = "COMMA"
+	LEFT_PAREN    = "LEFT_PAREN"
+	RIGHT_PAREN   = "RIGHT_PAREN"
+	LEFT_BRACE    = "LEFT_BRACE"
+	RIGHT_BRACE   = "RIGHT_BRACE"
+	LEFT_BRACKET  = "LEFT_BRACKET"
+	RIGHT_BRACKET = "RIGHT_BRACKET"
+	SEMICOLON     = "SEMICOLON"
+	COMMA         = "COMMA"
+
+	QUESTION          = "QUESTION"
+	COLON             = "COLON"
+	QUESTION_QUESTION = "QUESTION_QUESTION"
+	QUESTION_COLON    = "QUESTION_COLON"
 
 	// Keywords
 	STRING     = "STRING"
 	NUMBER     = "NUMBER"
 	IDENTIFIER = "IDENTIFIER"
 
+	// INTERPOLATION is a string segment that ends in `${`; the tokens of the
+	// embedded expression follow it, and the string resumes after the `}`.
+	INTERPOLATION = "INTERPOLATION"
+
 	// Reserved Keywords
 	AND      = "AND"
 	CLASS    = "CLASS"
@@ -61,25 +92,35 @@ const (
 	TRUE     = "TRUE"
 	VAR      = "VAR"
 	WHILE    = "WHILE"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
+	IMPORT   = "IMPORT"
 )
 
 var keywords = map[string]TokenType{
-	"and":    AND,
-	"class":  CLASS,
-	"else":   ELSE,
-	"false":  FALSE,
-	"for":    FOR,
-	"fun":    FUNCTION,
-	"if":     IF,
-	"nil":    NIL,
-	"or":     OR,
-	"print":  PRINT,
-	"return": RETURN,
-	"super":  SUPER,
-	"this":   THIS,
-	"true":   TRUE,
-	"var":    VAR,
-	"while":  WHILE,
+	"and":     AND,
+	"class":   CLASS,
+	"else":    ELSE,
+	"false":   FALSE,
+	"for":     FOR,
+	"fun":     FUNCTION,
+	"if":      IF,
+	"nil":     NIL,
+	"or":      OR,
+	"print":   PRINT,
+	"return":  RETURN,
+	"super":   SUPER,
+	"this":    THIS,
+	"true":    TRUE,
+	"var":     VAR,
+	"while":   WHILE,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"throw":   THROW,
+	"import":  IMPORT,
 }
 
 func New(tokenType TokenType, lexeme, literal string, line int) Token {